@@ -1,204 +1,168 @@
-package main
+package gencf
 
 import (
 	"bytes"
 	"fmt"
-	"go/ast"
-	"go/token"
-	"text/template"
+	"strings"
 )
 
-func structToHtml(a *ast.Field, structName string) (err error) {
-	defer func() {
-		if err != nil {
-			ast.Print(token.NewFileSet(), a)
-		}
-	}()
-
-	var f field
-	err = f.Parse(a, structName)
-	if err != nil {
-		return fmt.Errorf("Field: %v\n%v", f, err)
+// structToHtml write Go code with html of field into function writeHtml
+func (g *generator) structToHtml(source *bytes.Buffer, f *Field) {
+	source.WriteString("\n")
+	source.WriteString(fmt.Sprintf("	/"+"/ Field : %v\n", f.Path)) // comment
+
+	widget, data, ok := g.widgetData(f)
+	if !ok {
+		// TODO : Uncomment : err = fmt.Errorf("Type is not supported: %T", v)
+		source.WriteString(fmt.Sprintf("\n\n// Type is not supported: %s\n\n", f.Type))
+		return
 	}
+	source.WriteString(fmt.Sprintf("\tif err = %s; err != nil {\n\t\treturn\n\t}\n", render(widget, data)))
+}
+
+// render return Go expression, which write widget template with data into
+// w. Data with slots is created by function with argument `s`.
+func render(widget, data string) string {
+	if !strings.Contains(data, "s.slot(") && !strings.Contains(data, "s.items(") {
+		return fmt.Sprintf("gencfWrite(w, %q, %s)", widget, data)
+	}
+	return fmt.Sprintf("gencfRender(w, %q, func(s *gencfSlots) interface{} {\nreturn %s\n})", widget, data)
+}
 
+// slot return Go expression with html of slot written by Go statements.
+// Statements write into `w` and end by return.
+func slot(statements string) string {
+	return fmt.Sprintf("s.slot(func(w io.Writer) (err error) {\n%s\n})", statements)
+}
+
+// fieldData return Go expression with data of widget template for field.
+// Returned false for not supported field.
+func (g *generator) fieldData(f *Field) (data string, ok bool) {
 	var buf bytes.Buffer
-	Parameter.Source.WriteString("\n")
-	Parameter.Source.WriteString(fmt.Sprintf("	/"+"/ Field : %v\n", f.FieldName)) // comment
-	// add docs
-	if f.Docs != "" {
-		Parameter.Source.WriteString(fmt.Sprintf(
-			"\n\n\tout += fmt.Sprintf(\"\\n<br><strong>%s</strong><br>\\n\")\n", f.Docs))
+	buf.WriteString("gencfField{\n")
+	buf.WriteString(fmt.Sprintf("Name: prefix + %q,\n", f.Path))
+	buf.WriteString(fmt.Sprintf("ID: gencfID(opts.ID, prefix+%q),\n", f.Path))
+	label, help := f.Label, f.Help
+	if nested, ok := g.forms[f.Type]; ok && f.Kind == KindStruct && label == "" {
+		// documentation of nested struct
+		label, help = splitDoc(nested.Doc)
+	}
+	key := f.Struct + "." + f.Path
+	g.message(key, label)
+	buf.WriteString(fmt.Sprintf("Label: gencfText(opts.Locale, %q, %q),\n", key, label))
+	if help != "" {
+		g.message(helpKey(key), help)
+		buf.WriteString(fmt.Sprintf("Help: gencfText(opts.Locale, %q, %q),\n", helpKey(key), help))
 	}
+	buf.WriteString(fieldProperties(f))
+
+	switch f.Kind {
+	case KindBasic:
+		buf.WriteString(fmt.Sprintf("Value: value.%s,\n", f.Path))
+		buf.WriteString(fmt.Sprintf("Error: opts.Errors[prefix+%q],\n", f.Path))
+
+	case KindGroup:
+		buf.WriteString(fmt.Sprintf("HTML: %s,\n", g.groupHtml(f)))
+
+	case KindFile:
+		buf.WriteString(fmt.Sprintf("Error: opts.Errors[prefix+%q],\n", f.Path))
+
+	case KindStruct:
+		buf.WriteString(fmt.Sprintf("Value: value.%s,\n", f.Path))
+		buf.WriteString(fmt.Sprintf("HTML: %s,\n", slot(fmt.Sprintf(
+			"return value.%s.writeHtml(w, prefix+%q, opts)", f.Path, f.Path+"."))))
+
+	case KindSliceBasic:
+		g.addImport("fmt")
+		item := g.itemTemplate(f)
+		buf.WriteString(fmt.Sprintf("Value: value.%s,\n", f.Path))
+		buf.WriteString("Labels: gencfSliceText(opts.Locale),\n")
+		buf.WriteString(fmt.Sprintf(`Items: s.items(len(value.%[1]s), func(w io.Writer, i int) error {
+	name := fmt.Sprintf("%%s%[1]s[%%d]", prefix, i)
+	return gencfWrite(w, %[2]q, gencfField{
+		Name: name,
+		ID: gencfID(opts.ID, name),
+		%[3]sValue: value.%[1]s[i],
+		Error: opts.Errors[name],
+	})
+}),
+New: s.slot(func(w io.Writer) error {
+	return gencfWrite(w, %[2]q, gencfField{
+		Name: prefix + "%[1]s[__index__]",
+		ID: gencfID(opts.ID, prefix+"%[1]s[__index__]"),
+		%[3]sValue: *new(%[4]s),
+	})
+}),
+`, f.Path, item, fieldProperties(f), f.Type))
+
+	case KindSliceStruct:
+		g.addImport("fmt")
+		g.addImport("html/template")
+		g.addImport("strings")
+		buf.WriteString(fmt.Sprintf("Value: value.%s,\n", f.Path))
+		buf.WriteString("Labels: gencfSliceText(opts.Locale),\n")
+		buf.WriteString(fmt.Sprintf(`Items: s.items(len(value.%[1]s), func(w io.Writer, i int) error {
+	return value.%[1]s[i].writeHtml(w, fmt.Sprintf("%%s%[1]s[%%d].", prefix, i), opts)
+}),
+New: func() template.HTML {
+	// new element of slice in new element is not supported
+	if strings.Contains(prefix, "[__index__]") {
+		return ""
+	}
+	return s.slot(func(w io.Writer) error {
+		return %[2]s{}.writeHtml(w, prefix+"%[1]s[__index__].", opts)
+	})
+}(),
+`, f.Path, f.Type))
 
-	// imports
-	AddImport("fmt")
-
-	// convert types
-	switch v := a.Type.(type) {
-	case *ast.StructType:
-		// parse nested struct
-		for _, fss := range v.Fields.List {
-			err = structToHtml(fss, f.StructName+f.FieldName+".")
-			if err != nil {
-				return
-			}
-		}
-
-	case *ast.Ident:
-		switch v.Name {
-		// Go`s basic types
-		case "bool",
-			"string",
-			"int", "int8", "int16", " int32", "int64",
-			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
-			"byte", // alias for uint8
-			"rune", // alias for int32 represents a Unicode code point
-			"float32", "float64",
-			"complex64", "complex128":
-
-			// imports
-			AddImport("fmt")
-
-			// template
-			tmpl := `out += fmt.Sprintf(
-	"\n<input type=\"text\" name=\"%s{{ .FieldName }}\" value=\"%s\"><br>\n",
-	prefix, fmt.Sprintf("%v", value{{ .FieldNameWithFirstPoint }}))`
-
-			t := template.New("Ident template")
-			if t, err = t.Parse(tmpl); err != nil {
-				return
-			}
-
-			if err = t.Execute(&buf, f); err != nil {
-				return
-			}
-
-		default: // user struct
-			buf.WriteString(
-				"out += value.toHtml(fmt.Sprintf(\"%s" + f.FieldName + ".\",prefix))")
-		}
-
-	case *ast.ArrayType:
-		// Example
-		//
-		// *ast.ArrayType {
-		// .  Lbrack: -
-		// .  Elt: *ast.Ident {
-		// .  .  NamePos: -
-		// .  .  Name: "string"
-		// .  }
-		// }
-		switch v.Elt.(*ast.Ident).Name {
-		// Go`s basic types
-		case "bool",
-			"string",
-			"int", "int8", "int16", " int32", "int64",
-			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
-			"byte", // alias for uint8
-			"rune", // alias for int32 represents a Unicode code point
-			"float32", "float64",
-			"complex64", "complex128":
-
-			// imports
-			AddImport("fmt")
-
-			// template
-			tmpl := `
-	// 
-	// Exist elements of field: {{ .FieldName }}
-	//
-	for i := range value{{ .FieldNameWithFirstPoint }}{
-		out += fmt.Sprintf("Data %d<br>\n",i)
-		out += fmt.Sprintf(
-			"\n<input type=\"text\" name=\"%s{{ .FieldName }}[%d]\" value=\"%s\"><br>\n",
-			prefix,i, fmt.Sprintf("%v", value{{ .FieldNameWithFirstPoint }}))
+	default:
+		return "", false
 	}
 
-	//
-	// Array script of : {{ .FieldName }} 
-	//
-	out += "<script>\n"
-	out += fmt.Sprintf("var initVal{{ .FieldName }} = %d;\n",len(value{{ .FieldNameWithFirstPoint}}))
-	out += "\n"
-	out += "function insertAfter{{ .FieldName }}(elem, refElem) { \n"
-	out += "  console.log('Insert : ' + elem + ' in ' + refElem);\n"
-	out += "  var parent = refElem.parentNode; \n"
-	out += "  var next = refElem.nextSibling; \n"
-	out += "  if (next) { \n"
-	out += "    return parent.insertBefore(elem, next); \n"
-	out += "  } else { \n"
-	out += "    return parent.appendChild(elem); \n"
-	out += "  } \n"
-	out += "} \n"
-
-	out += "function createEl{{ .FieldName }}(context) { \n"
-	out += "	console.log('context : '+ context);\n"
-	out += "	// create label\n"
-	out += "	var txt = document.createElement(\"p\"); \n"
-	out += "    var id  = \"Input{{ .StructName }}\"+initVal{{ .FieldName }}+\"{{ .FieldName }}\" ;\n " 
-	out += "	txt.id   = id;\n"
-	out += "	var node = document.createTextNode('Data '+ initVal{{ .FieldName }});\n"
-	out += "	txt.appendChild(node);\n"
-	out += "	console.log(el);\n"
-	out += "	insertAfter{{ .FieldName }}(txt,context); \n"
-	out += "	// create input\n"
-	out += "	var el = document.createElement(\"input\"); \n"
-	out += "	el.type = \"text\"; \n"
-	out += "	el.name = \"{{ .StructName }}{{ .FieldName }}[\"+initVal{{ .FieldName }}+\"]\"; \n"
-	out += "	var last = id;\n"
-	out += "	id = \"Text{{ .StructName }}\"+initVal{{ .FieldName }}+\"{{ .FieldName }}\" ;\n " 
-	out += "	el.id   = id;\n"
-	out += "	console.log(el);\n"
-	out += "	insertAfter{{ .FieldName }}(el, document.getElementById(last)); \n"
-	out += "	// incrementation\n"
-	out += "	initVal{{ .FieldName }}++; \n"
-	out += "	console.log(\"initVal = \" + initVal{{ .FieldName }});\n"
-	out += "	// create br\n"
-	out += "	console.log('create label');\n"
-	out += "	var label = document.createElement(\"br\");\n"
-	out += "	label.id = 'breakLine' + initVal{{ .FieldName }} + '{{ .FieldName }}';\n";
-	out += "	console.log(label);\n"
-	out += "	insertAfter{{ .FieldName }} (label, document.getElementById(id));\n"
-	out += " } \n"
-
-	out += "function add{{ .FieldName }}() { \n"
-	out += "	console.log(\"initVal = \" + initVal{{ .FieldName }});\n"
-	out += "	var name = 'breakLine' + initVal{{ .FieldName }} + '{{ .FieldName }}'; \n"
-	out += "	console.log('name of parent : ' + name);\n "
-	out += "	createEl{{ .FieldName }}(document.getElementById(name)); \n"
-	out += "	console.log(\"initVal = \" + initVal{{ .FieldName }});\n"
-	out += "} \n"
-	out += "</script>\n"
-
-	out += "<button type=\"button\" OnClick=\"add{{ .FieldName }}()\">+</button>\n"
-	out += fmt.Sprintf("<br id=\"breakLine%d{{ .FieldName }}\">\n",len(value{{ .FieldNameWithFirstPoint }}))
-
-	`
-
-			t := template.New("Ident template")
-			if t, err = t.Parse(tmpl); err != nil {
-				return
-			}
-
-			if err = t.Execute(&buf, f); err != nil {
-				return
-			}
-
-		default:
-			// ast.Print(token.NewFileSet(), v)
-			// TODO : Uncomment : err = fmt.Errorf("Type is not supported of array: %T. %#v", v, v.Elt)
-			Parameter.Source.WriteString(fmt.Sprintf("\n\n// Type is not supported of array: %T. %#v", v, v.Elt.(*ast.Ident).Name))
-			return
-		}
+	buf.WriteString("}")
+	return buf.String(), true
+}
 
-	default:
-		// TODO : Uncomment : err = fmt.Errorf("Type is not supported: %T", v)
-		Parameter.Source.WriteString(fmt.Sprintf("\n\n// Type is not supported: %T\n\n", v))
-		return
+// fieldProperties return Go code with properties of widget data, which
+// are same for field and slice elements
+func fieldProperties(f *Field) string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Type: %q,\n", f.Type))
+	buf.WriteString(fmt.Sprintf("Widget: %q,\n", f.Widget))
+	if f.Constraints.Required {
+		buf.WriteString("Required: true,\n")
+	}
+	if f.Constraints.Min != "" {
+		buf.WriteString(fmt.Sprintf("Min: %q,\n", f.Constraints.Min))
+	}
+	if f.Constraints.Max != "" {
+		buf.WriteString(fmt.Sprintf("Max: %q,\n", f.Constraints.Max))
+	}
+	if f.Constraints.Pattern != "" {
+		buf.WriteString(fmt.Sprintf("Pattern: %q,\n", f.Constraints.Pattern))
 	}
+	if len(f.Options) > 0 {
+		buf.WriteString(fmt.Sprintf("Options: %s,\n", stringsLiteral(f.Options)))
+	}
+	if len(f.Accept) > 0 {
+		buf.WriteString(fmt.Sprintf("Accept: %q,\n", strings.Join(f.Accept, ",")))
+	}
+	return buf.String()
+}
 
-	Parameter.Source.WriteString(buf.String())
-	Parameter.Source.WriteString("\n\n\n")
+// stringsLiteral return Go expression of slice of strings
+func stringsLiteral(ss []string) string {
+	if len(ss) == 0 {
+		return "nil"
+	}
+	var quoted []string
+	for _, s := range ss {
+		quoted = append(quoted, fmt.Sprintf("%q", s))
+	}
+	return fmt.Sprintf("[]string{%s}", strings.Join(quoted, ", "))
+}
 
-	return
+// groupHtml return Go expression with html of group fields
+func (g *generator) groupHtml(f *Field) string {
+	return g.fieldsHtml(groupTemplateName(f), f.Fields)
 }
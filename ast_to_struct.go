@@ -1,119 +1,238 @@
-package main
-
-import "go/ast"
-
-func HtmlToStruct(a *ast.Field, structName string) (err error) {
-	// 	par = fmt.Sprintf("func (value *%s) FromHtml(r *http.Request) (err error) {\n", name) +
-	// 		"	et := errors.New(\"Errors of convert\")\n" +
-	// 		par
-
-	// // -----------------
-	// buf.Reset()
-	// switch v.Name {
-	//
-	// // float64
-	// case "float64":
-	// 	tmpl = `
-	// 	{
-	// 		// check if exist
-	// 		if str, ok := r.Form["{{ .Name }}"]; ok{
-	// 			if len(str) == 1 {
-	// 				v, err := strconv.ParseFloat(str[0],64)
-	// 				if err != nil {
-	// 					et.Add(err)
-	// 				} else {
-	// 					{{ .ValueName }} = v
-	// 				}
-	// 			}
-	// 		}
-	// 	}
-	// `
-	// 	t := template.New("Person template")
-	//
-	// 	t, err := t.Parse(tmpl)
-	// 	if err != nil {
-	// 		panic(err)
-	// 	}
-	//
-	// 	err = t.Execute(&buf, y)
-	// 	if err != nil {
-	// 		panic(err)
-	// 	}
-	//
-	// // string
-	// case "string":
-	// 	tmpl = `
-	// 	{
-	// 		if str, ok := r.Form["{{ .Name }}"]; ok{
-	// 			if len(str) == 1 {
-	// 				{{ .ValueName }} = str[0]
-	// 			}
-	// 		}
-	// 	}
-	// `
-	// 	t := template.New("Person template")
-	//
-	// 	t, err := t.Parse(tmpl)
-	// 	if err != nil {
-	// 		panic(err)
-	// 	}
-	//
-	// 	err = t.Execute(&buf, y)
-	// 	if err != nil {
-	// 		panic(err)
-	// 	}
-	//
-	// // int
-	// case "int":
-	// 	tmpl = `
-	// 	{
-	// 		// check if exist
-	// 		if str, ok := r.Form["{{ .Name }}"]; ok{
-	// 			if len(str) == 1 {
-	// 				v, err := strconv.ParseInt(str[0],10,64)
-	// 				if err != nil {
-	// 					et.Add(err)
-	// 				} else {
-	// 					{{ .ValueName }} = int(v)
-	// 				}
-	// 			}
-	// 		}
-	// 	}
-	// `
-	// 	t := template.New("Person template")
-	//
-	// 	t, err := t.Parse(tmpl)
-	// 	if err != nil {
-	// 		panic(err)
-	// 	}
-	//
-	// 	err = t.Execute(&buf, y)
-	// 	if err != nil {
-	// 		panic(err)
-	// 	}
-	//
-	// default:
-	// 	fmt.Println("> Type >", v.Name)
-	// }
-	//
-	// 		par += "\n"
-	// 		par += fmt.Sprintf("	/"+"/ Name : %v\n", y.Name)
-	// 		par += fmt.Sprintf("	/"+"/ Type : %v\n", v.Name)
-	// 		par += buf.String()
-	// 		par += "\n"
-	//
-	// 	default:
-	// 		// debug
-	// 		ast.Print(token.NewFileSet(), fs.Type)
-	// 	}
-	// }
-
-	//
-	// 	par += "	if (et.IsError()){\n"
-	// 	par += "		return et\n"
-	// 	par += "	}\n"
-	// 	par += "	return\n"
-	// 	par += "}\n"
+package gencf
 
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// decodeRuntime is Go source of types and functions for decode form values
+const decodeRuntime = `
+// FormErrors is errors of form values by names of html inputs.
+// Error of whole form have empty name.
+type FormErrors map[string]string
+
+func (errs FormErrors) Error() string {
+	var names []string
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%s: %s", name, errs[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// gencfError add error of html input, if input have not error
+func gencfError(errs FormErrors, name, message string) {
+	if _, ok := errs[name]; !ok {
+		errs[name] = message
+	}
+}
+
+// gencfIndexes return sorted indexes of slice elements in form values
+func gencfIndexes(form url.Values, name string) (indexes []int) {
+	exist := map[int]bool{}
+	for key := range form {
+		if !strings.HasPrefix(key, name+"[") {
+			continue
+		}
+		key = key[len(name)+1:]
+		end := strings.Index(key, "]")
+		if end < 0 {
+			continue
+		}
+		index, err := strconv.Atoi(key[:end])
+		if err != nil || index < 0 || exist[index] {
+			continue
+		}
+		exist[index] = true
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
 	return
 }
+
+// gencfParseBool return value of checkbox
+func gencfParseBool(s, name string, errs FormErrors) bool {
+	if s == "" {
+		return false
+	}
+	if s == "on" {
+		return true
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		gencfError(errs, name, "not valid boolean value")
+	}
+	return v
+}
+
+// gencfParseInt return integer value of html input
+func gencfParseInt(s, name string, bitSize int, errs FormErrors) int64 {
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, bitSize)
+	if err != nil {
+		gencfError(errs, name, "not valid integer value")
+	}
+	return v
+}
+
+// gencfParseUint return unsigned integer value of html input
+func gencfParseUint(s, name string, bitSize int, errs FormErrors) uint64 {
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseUint(s, 10, bitSize)
+	if err != nil {
+		gencfError(errs, name, "not valid unsigned integer value")
+	}
+	return v
+}
+
+// gencfParseFloat return float value of html input
+func gencfParseFloat(s, name string, bitSize int, errs FormErrors) float64 {
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, bitSize)
+	if err != nil {
+		gencfError(errs, name, "not valid number")
+	}
+	return v
+}
+
+// gencfParseComplex return complex value of html input
+func gencfParseComplex(s, name string, bitSize int, errs FormErrors) complex128 {
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseComplex(s, bitSize)
+	if err != nil {
+		gencfError(errs, name, "not valid complex number")
+	}
+	return v
+}
+`
+
+// decode write Go source of method decode for struct
+func (g *generator) decode(form *Form) {
+	g.addImport("mime/multipart")
+	g.addImport("net/url")
+	g.source.WriteString(fmt.Sprintf(
+		"\nfunc (value *%s) decode(form url.Values, files map[string][]*multipart.FileHeader, prefix string, errs FormErrors) {\n",
+		form.Name))
+	for _, f := range form.Fields {
+		g.htmlToStruct(&g.source, f)
+	}
+	g.source.WriteString("}\n")
+}
+
+// htmlToStruct write Go code for decode value of field from form values
+func (g *generator) htmlToStruct(source *bytes.Buffer, f *Field) {
+	source.WriteString("\n")
+	source.WriteString(fmt.Sprintf("	/"+"/ Field : %v\n", f.Path)) // comment
+
+	switch f.Kind {
+	case KindBasic:
+		name := fmt.Sprintf("prefix+%q", f.Path)
+		source.WriteString(fmt.Sprintf("\tvalue.%s = %s\n",
+			f.Path, parseValue(f.Type, "form.Get("+name+")", name)))
+
+	case KindGroup:
+		for _, ff := range f.Fields {
+			g.htmlToStruct(source, ff)
+		}
+
+	case KindStruct:
+		source.WriteString(fmt.Sprintf("\tvalue.%s.decode(form, files, prefix+%q, errs)\n",
+			f.Path, f.Path+"."))
+
+	case KindFile:
+		for _, message := range fileMessages {
+			g.message(message, message)
+		}
+		max := -1 // without limit
+		if n, err := strconv.Atoi(f.Constraints.Max); err == nil && n >= 0 {
+			max = n
+		}
+		file := fmt.Sprintf("gencfFile(files, prefix+%q, %s, %d, errs)",
+			f.Path, stringsLiteral(f.Accept), max)
+		if f.Type == "[]byte" {
+			source.WriteString(fmt.Sprintf("\tvalue.%s = %s.Data\n", f.Path, file))
+			break
+		}
+		g.addImport(importPath)
+		source.WriteString(fmt.Sprintf("\tvalue.%s = gencf.File(%s)\n", f.Path, file))
+
+	case KindSliceBasic:
+		value := parseValue(f.Type, "form.Get(name)", "name")
+		g.decodeSlice(source, f,
+			fmt.Sprintf("name := fmt.Sprintf(\"%%s%s[%%d]\", prefix, i)\n", f.Path),
+			fmt.Sprintf("value.%s[i] = %s\n", f.Path, value),
+			fmt.Sprintf("value.%[1]s = append(value.%[1]s, %[2]s)\n", f.Path, value))
+
+	case KindSliceStruct:
+		item := fmt.Sprintf("fmt.Sprintf(\"%%s%s[%%d].\", prefix, i)", f.Path)
+		g.decodeSlice(source, f, "",
+			fmt.Sprintf("value.%s[i].decode(form, files, %s, errs)\n", f.Path, item),
+			fmt.Sprintf("var item %s\nitem.decode(form, files, %s, errs)\n", f.Type, item)+
+				fmt.Sprintf("value.%[1]s = append(value.%[1]s, item)\n", f.Path))
+
+	default:
+		source.WriteString(fmt.Sprintf("\n\n// Type is not supported: %s\n\n", f.Type))
+	}
+}
+
+// decodeSlice write Go code for decode elements of slice or array.
+// Index of element is `i`.
+func (g *generator) decodeSlice(source *bytes.Buffer, f *Field, name, array, slice string) {
+	g.addImport("fmt")
+	if f.Array {
+		source.WriteString(fmt.Sprintf("\tfor _, i := range gencfIndexes(form, prefix+%q) {\n", f.Path))
+		source.WriteString(fmt.Sprintf("if len(value.%s) <= i {\ncontinue\n}\n", f.Path))
+		source.WriteString(name + array + "}\n")
+		return
+	}
+	source.WriteString(fmt.Sprintf("\tvalue.%s = nil\n", f.Path))
+	source.WriteString(fmt.Sprintf("\tfor _, i := range gencfIndexes(form, prefix+%q) {\n", f.Path))
+	source.WriteString(name + slice + "}\n")
+}
+
+// parseValue return Go expression of value with Go basic type `typ`
+// from string expression `s`. Name of html input is expression `name`.
+func parseValue(typ, s, name string) string {
+	var parse string
+	var bitSize int
+	switch typ {
+	case "string":
+		return s
+	case "bool":
+		return fmt.Sprintf("gencfParseBool(%s, %s, errs)", s, name)
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		parse = "gencfParseInt"
+	case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte":
+		parse = "gencfParseUint"
+	case "float32", "float64":
+		parse = "gencfParseFloat"
+	case "complex64", "complex128":
+		parse = "gencfParseComplex"
+	}
+	switch typ {
+	case "int8", "uint8", "byte":
+		bitSize = 8
+	case "int16", "uint16":
+		bitSize = 16
+	case "int32", "uint32", "rune", "float32":
+		bitSize = 32
+	case "int64", "uint64", "float64", "complex64":
+		bitSize = 64
+	case "complex128":
+		bitSize = 128
+	}
+	return fmt.Sprintf("%s(%s(%s, %s, %d, errs))", typ, parse, s, name, bitSize)
+}
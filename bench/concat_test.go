@@ -0,0 +1,68 @@
+package bench
+
+// Types of file are copy of types Large and Node without tags. Methods ToHtml
+// of file concat_gen_test.go are generated for that types by gencf before
+// streaming (commit 96cce55) by command:
+//
+//	gencf -struct=concatLarge -struct=concatNode -p=bench -i=concat_test.go -o=concat_gen_test.go
+//
+// Generated code is changed by hand only for:
+//   - value of slice element in loop of Loads is `value.Loads[i]` instead of
+//     `value.Loads`, because html of all slice for each element is mistake;
+//   - loop of Nodes is added, because slices of structs are not supported;
+//   - methods FormDefault are removed.
+
+// concatLarge is struct of benchmark of html by string concatenation
+type concatLarge struct {
+	// name of model
+	Name string
+
+	// count of iterations
+	Iterations int
+
+	// tolerance of solution
+	Tolerance float64
+
+	// solver options
+	Solver struct {
+		// name of solver
+		Name string
+
+		// use parallel solver
+		Parallel bool
+
+		// amount of threads of parallel solver
+		Threads int
+	}
+
+	// values of load
+	Loads []float64
+
+	// nodes of model
+	Nodes []concatNode
+}
+
+// concatNode is point of model
+type concatNode struct {
+	// index of node
+	Index int
+
+	// coordinate X
+	X float64
+
+	// coordinate Y
+	Y float64
+
+	// coordinate Z
+	Z float64
+}
+
+// concat return struct with `size` elements in each slice like function large
+func concat(size int) (c concatLarge) {
+	c.Name = "model"
+	for i := 0; i < size; i++ {
+		c.Loads = append(c.Loads, float64(i))
+		c.Nodes = append(c.Nodes, concatNode{Index: i, X: float64(i)})
+	}
+	return
+}
@@ -0,0 +1,71 @@
+// Package bench is large struct with generated methods for
+// tests and benchmarks of generated code.
+package bench
+
+//go:generate gencf -struct=Large -struct=Node -p=bench -o=large_gen.go -i=large.go
+//go:generate gencf -struct=Account -p=bench -o=account_gen.go -i=large.go
+
+// Large is struct with many fields and long slices
+type Large struct {
+	// name of model
+	Name string `form:"required,pattern=[a-z]+"`
+
+	// count of iterations
+	Iterations int `form:"min=1,max=1000"`
+
+	// tolerance of solution
+	Tolerance float64
+
+	// solver options
+	Solver struct {
+		// name of solver
+		Name string
+
+		// use parallel solver
+		Parallel bool
+
+		// amount of threads of parallel solver
+		Threads int `form:"required,min=1,showif=Solver.Parallel"`
+	}
+
+	// values of load
+	Loads []float64
+
+	// nodes of model
+	Nodes []Node
+
+	// report of calculation
+	Report []byte `form:"widget=file,accept=text/csv|.csv,max=64"`
+}
+
+// Node is point of model
+type Node struct {
+	// index of node
+	Index int `form:"min=0"`
+
+	// coordinate X
+	X float64
+
+	// coordinate Y
+	Y float64
+
+	// coordinate Z
+	Z float64
+}
+
+// Account is wizard of new account
+//
+//gencf:layout=wizard
+type Account struct {
+	// name of user
+	Name string `form:"required"`
+
+	// email of user
+	Email string `form:"group=Contact,widget=email,required"`
+
+	// phone of user
+	Phone string `form:"group=Contact"`
+
+	// subscription to newsletter
+	Newsletter bool
+}
@@ -0,0 +1,961 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// large return struct with `size` elements in each slice
+func large(size int) (l Large) {
+	l.Name = "model"
+	for i := 0; i < size; i++ {
+		l.Loads = append(l.Loads, float64(i))
+		l.Nodes = append(l.Nodes, Node{Index: i, X: float64(i)})
+	}
+	return
+}
+
+// form return html page with form
+func form(t *testing.T, value interface {
+	Form(opts FormOptions) (string, error)
+}, opts FormOptions) string {
+	t.Helper()
+	html, err := value.Form(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return html
+}
+
+func TestWriteHtml(t *testing.T) {
+	l := large(10)
+	var buf bytes.Buffer
+	if err := l.WriteHtml(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := buf.String(), l.ToHtml(); act != exp {
+		t.Errorf("html is not same:\n%s\n%s", act, exp)
+	}
+}
+
+// countWriter is writer with statistics of writes. Writes after limit
+// are failed.
+type countWriter struct {
+	writes, size, max, limit int
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	c.writes++
+	if 0 < c.limit && c.limit < c.writes {
+		return 0, fmt.Errorf("write %d is failed", c.writes)
+	}
+	c.size += len(p)
+	if c.max < len(p) {
+		c.max = len(p)
+	}
+	return len(p), nil
+}
+
+func TestStream(t *testing.T) {
+	l := large(1000)
+	for name, write := range map[string]func(w io.Writer) error{
+		"WriteHtml": l.WriteHtml,
+		"WriteForm": func(w io.Writer) error {
+			return l.WriteForm(w, FormOptions{})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var c countWriter
+			if err := write(&c); err != nil {
+				t.Fatal(err)
+			}
+			if c.size < 100*c.max {
+				t.Errorf("html is not streamed: %d bytes by writes up to %d bytes", c.size, c.max)
+			}
+
+			// error of nested html
+			fail := countWriter{limit: c.writes / 2}
+			if err := write(&fail); err == nil {
+				t.Errorf("error of write is not returned")
+			}
+			if fail.writes != fail.limit+1 {
+				t.Errorf("html is written after error: %d writes", fail.writes-fail.limit-1)
+			}
+		})
+	}
+}
+
+func TestForm(t *testing.T) {
+	var n Node
+	tcs := []struct {
+		opts FormOptions
+		exp  []string
+	}{
+		{
+			opts: FormOptions{Action: "/node"},
+			exp: []string{
+				`<form action="/node" method="POST">`,
+				`<input type="submit" value="Submit">`,
+			},
+		},
+		{
+			opts: FormOptions{
+				Action:  "/node",
+				Method:  "GET",
+				Target:  "_blank",
+				Enctype: "multipart/form-data",
+				ID:      "node",
+				Class:   "form wide",
+				Hidden:  map[string]string{"b": "2", "a": "1"},
+				Submit:  "Save",
+			},
+			exp: []string{
+				`<form id="node" class="form wide" action="/node" method="GET" target="_blank" enctype="multipart/form-data">`,
+				`<input type="hidden" name="a" value="1">` + "\n" +
+					`<input type="hidden" name="b" value="2">`,
+				`<input type="submit" value="Save">`,
+			},
+		},
+		{
+			opts: FormOptions{Script: "/gencf.js", Nonce: "abc"},
+			exp: []string{
+				`<script type="module" src="/gencf.js" nonce="abc"></script>`,
+			},
+		},
+		{
+			opts: FormOptions{Nonce: "abc"},
+			exp: []string{
+				`<script type="module" nonce="abc">`,
+				`data-gencf-add`,
+			},
+		},
+	}
+	for i, tc := range tcs {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			html := form(t, n, tc.opts)
+			for _, exp := range tc.exp {
+				if !strings.Contains(html, exp) {
+					t.Errorf("html have not %q:\n%s", exp, html)
+				}
+			}
+		})
+	}
+}
+
+func TestScript(t *testing.T) {
+	// without inline script and event handlers
+	html := form(t, large(2), FormOptions{})
+	for _, s := range []string{"<script", "onclick"} {
+		if strings.Contains(html, s) {
+			t.Errorf("html have %q:\n%s", s, html)
+		}
+	}
+
+	// controls of slice elements
+	for _, s := range []string{
+		`<div data-gencf-item="1">`,
+		`<div data-gencf-item="__index__">`,
+		`data-gencf-remove`, `data-gencf-up`, `data-gencf-down`, `data-gencf-drag`,
+	} {
+		if !strings.Contains(html, s) {
+			t.Errorf("html have not %q:\n%s", s, html)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	ScriptHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/gencf.js", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/javascript") {
+		t.Errorf("not valid content type: %s", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "data-gencf-add") {
+		t.Errorf("not valid script:\n%s", rec.Body.String())
+	}
+}
+
+func TestID(t *testing.T) {
+	l := large(2)
+	page := form(t, l, FormOptions{ID: "first"}) + form(t, l, FormOptions{ID: "second"})
+	for _, exp := range []string{
+		`<label for="first-Large-Name">name of model</label>`,
+		`id="first-Large-Name" name="Large.Name"`,
+		`<fieldset id="second-Large-Solver">` + "\n<legend>solver options</legend>",
+		`<label for="second-Large-Solver-Name">name of solver</label>`,
+		`<label for="first-Large-Loads-1">`,
+		`id="first-Large-Loads-1" name="Large.Loads[1]"`,
+		`id="first-Large-Nodes-0-X" name="Large.Nodes[0].X"`,
+	} {
+		if !strings.Contains(page, exp) {
+			t.Errorf("html have not %q", exp)
+		}
+	}
+
+	// ids are unique on page
+	ids := map[string]bool{}
+	for _, m := range regexp.MustCompile(` id="([^"]+)"`).FindAllStringSubmatch(page, -1) {
+		if ids[m[1]] {
+			t.Errorf("id %q is not unique", m[1])
+		}
+		ids[m[1]] = true
+	}
+}
+
+func TestLocale(t *testing.T) {
+	AddCatalog("de", map[string]string{
+		"Large":             "Modell",
+		"Large.Name":        "Name des Modells",
+		"value is required": "Wert ist erforderlich",
+		"Submit":            "Senden",
+		"Add":               "Neu",
+		"Remove":            "Entfernen",
+	})
+	if err := LoadCatalog("de-AT", strings.NewReader(`{"Large.Name": "Modellname"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var l Large
+	err := l.FromForm(url.Values{})
+	errs, ok := err.(FormErrors)
+	if !ok {
+		t.Fatalf("not valid errors: %v", err)
+	}
+	page := form(t, l, FormOptions{Locale: "de-AT", Errors: errs})
+	for _, exp := range []string{
+		`<html lang="de-AT">`,
+		`<legend>Modell</legend>`,
+		`<label for="gencf-Large-Name">Modellname</label>`,
+		`<label for="gencf-Large-Iterations">count of iterations</label>`,
+		`>Wert ist erforderlich</span>`,
+		`<input type="submit" value="Senden">`,
+		`title="Neu"`,
+		`data-gencf-remove title="Entfernen"`,
+	} {
+		if !strings.Contains(page, exp) {
+			t.Errorf("html have not %q:\n%s", exp, page)
+		}
+	}
+
+	// locale of request
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+	rec := httptest.NewRecorder()
+	NewLargeHandler(func(ctx context.Context, value *Large) error {
+		return nil
+	}).ServeHTTP(rec, r)
+	if !strings.Contains(rec.Body.String(), "Name des Modells") {
+		t.Errorf("not valid locale of request:\n%s", rec.Body.String())
+	}
+}
+
+func TestFromForm(t *testing.T) {
+	tcs := []struct {
+		form  url.Values
+		value Large
+		errs  FormErrors
+	}{
+		{
+			form: url.Values{
+				"Large.Name":               {"model"},
+				"Large.Iterations":         {"10"},
+				"Large.Tolerance":          {"1e-6"},
+				"Large.Solver.Name":        {"cg"},
+				"Large.Solver.Parallel":    {"true"},
+				"Large.Solver.Threads":     {"4"},
+				"Large.Loads[1]":           {"2.5"},
+				"Large.Loads[0]":           {"1.5"},
+				"Large.Nodes[0].Index":     {"1"},
+				"Large.Nodes[0].X":         {"0.5"},
+				"Large.Nodes[__index__].X": {"0"},
+			},
+			value: func() (l Large) {
+				l.Name = "model"
+				l.Iterations = 10
+				l.Tolerance = 1e-6
+				l.Solver.Name = "cg"
+				l.Solver.Parallel = true
+				l.Solver.Threads = 4
+				l.Loads = []float64{1.5, 2.5}
+				l.Nodes = []Node{{Index: 1, X: 0.5}}
+				return
+			}(),
+		},
+		{
+			form: url.Values{
+				"Large.Name":           {"Model"},
+				"Large.Iterations":     {"0"},
+				"Large.Tolerance":      {"small"},
+				"Large.Nodes[0].Index": {"-1"},
+			},
+			errs: FormErrors{
+				"Large.Name":           "value does not match pattern",
+				"Large.Iterations":     "value must be greater than or equal to 1",
+				"Large.Tolerance":      "not valid number",
+				"Large.Nodes[0].Index": "value must be greater than or equal to 0",
+			},
+		},
+		{
+			form: url.Values{},
+			errs: FormErrors{
+				"Large.Name":       "value is required",
+				"Large.Iterations": "value must be greater than or equal to 1",
+			},
+		},
+	}
+	for i, tc := range tcs {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var l Large
+			err := l.FromForm(tc.form)
+			if tc.errs != nil {
+				if !reflect.DeepEqual(err, tc.errs) {
+					t.Errorf("errors are not same:\n%v\n%v", err, tc.errs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(l, tc.value) {
+				t.Errorf("values are not same:\n%#v\n%#v", l, tc.value)
+			}
+		})
+	}
+}
+
+func TestShowIf(t *testing.T) {
+	l := large(0)
+	if html := l.ToHtml(); !strings.Contains(html, `data-gencf-showif="Large.Solver.Parallel" hidden disabled>`) {
+		t.Errorf("field of not parallel solver is shown:\n%s", html)
+	}
+	l.Solver.Parallel = true
+	if html := l.ToHtml(); strings.Contains(html, "hidden disabled") {
+		t.Errorf("field of parallel solver is hidden:\n%s", html)
+	}
+
+	form := url.Values{"Large.Name": {"model"}, "Large.Iterations": {"1"}}
+	if err := l.FromForm(form); err != nil {
+		t.Errorf("hidden field is validated: %v", err)
+	}
+	form.Set("Large.Solver.Parallel", "true")
+	err := l.FromForm(form)
+	if errs, ok := err.(FormErrors); !ok || errs["Large.Solver.Threads"] != "value is required" {
+		t.Errorf("shown field is not validated: %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var submitted *Large
+	h := NewLargeHandler(func(ctx context.Context, l *Large) error {
+		if l.Name == "busy" {
+			return fmt.Errorf("model is busy")
+		}
+		submitted = l
+		return nil
+	}, WithFormOptions(FormOptions{Submit: "Save"}))
+
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/large?id=1", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("get", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/large", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d", w.Code)
+		}
+		for _, exp := range []string{`method="POST"`, `name="Large.Name"`, `value="Save"`} {
+			if !strings.Contains(w.Body.String(), exp) {
+				t.Errorf("html have not %q:\n%s", exp, w.Body.String())
+			}
+		}
+	})
+	t.Run("not valid", func(t *testing.T) {
+		w := post(url.Values{"Large.Name": {"model"}, "Large.Iterations": {"abc"}})
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("status %d", w.Code)
+		}
+		for _, exp := range []string{
+			`name="Large.Name" value="model"`,
+			`<span class="gencf-error" id="gencf-Large-Iterations-error">not valid integer value</span>`,
+			`aria-invalid="true" aria-describedby="gencf-Large-Iterations-error"`,
+		} {
+			if !strings.Contains(w.Body.String(), exp) {
+				t.Errorf("html have not %q:\n%s", exp, w.Body.String())
+			}
+		}
+		if submitted != nil {
+			t.Errorf("not valid form is submitted")
+		}
+	})
+	t.Run("submit error", func(t *testing.T) {
+		w := post(url.Values{"Large.Name": {"busy"}, "Large.Iterations": {"1"}})
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("status %d", w.Code)
+		}
+		if exp := `<p class="gencf-error" role="alert">model is busy</p>`; !strings.Contains(w.Body.String(), exp) {
+			t.Errorf("html have not %q:\n%s", exp, w.Body.String())
+		}
+	})
+	t.Run("submit", func(t *testing.T) {
+		w := post(url.Values{"Large.Name": {"model"}, "Large.Iterations": {"5"}})
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("status %d", w.Code)
+		}
+		if loc := w.Header().Get("Location"); loc != "/large?id=1" {
+			t.Errorf("redirect to %q", loc)
+		}
+		if submitted == nil || submitted.Name != "model" || submitted.Iterations != 5 {
+			t.Errorf("submitted value: %#v", submitted)
+		}
+	})
+	t.Run("method", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/large", nil))
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("status %d", w.Code)
+		}
+	})
+}
+
+func TestAction(t *testing.T) {
+	submitted := false
+	h := NewLargeHandler(func(ctx context.Context, l *Large) error {
+		submitted = true
+		return nil
+	})
+	tcs := []struct {
+		action string
+		form   url.Values
+		exp    []string
+	}{
+		{
+			action: "add:Large.Loads",
+			form:   url.Values{"Large.Loads[0]": {"1"}},
+			exp:    []string{`name="Large.Loads[0]" value="1"`, `name="Large.Loads[1]" value="0"`},
+		},
+		{
+			action: "remove:Large.Loads[0]",
+			form:   url.Values{"Large.Loads[0]": {"1"}, "Large.Loads[1]": {"2"}},
+			exp:    []string{`name="Large.Loads[0]" value="2"`, `value="remove:Large.Loads[0]"`},
+		},
+		{
+			action: "down:Large.Nodes[0]",
+			form: url.Values{
+				"Large.Nodes[0].X": {"1"},
+				"Large.Nodes[5].X": {"2"},
+			},
+			exp: []string{`name="Large.Nodes[0].X" value="2"`, `name="Large.Nodes[1].X" value="1"`},
+		},
+		{
+			action: "up:Large.Nodes[5]",
+			form: url.Values{
+				"Large.Nodes[0].X": {"1"},
+				"Large.Nodes[5].X": {"2"},
+			},
+			exp: []string{`name="Large.Nodes[0].X" value="2"`, `name="Large.Nodes[1].X" value="1"`},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.action, func(t *testing.T) {
+			tc.form.Set("_action", tc.action)
+			r := httptest.NewRequest(http.MethodPost, "/large", strings.NewReader(tc.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d", w.Code)
+			}
+			for _, exp := range tc.exp {
+				if !strings.Contains(w.Body.String(), exp) {
+					t.Errorf("html have not %q:\n%s", exp, w.Body.String())
+				}
+			}
+			// required fields are not checked
+			if strings.Contains(w.Body.String(), "value is required") {
+				t.Errorf("values are validated")
+			}
+			if submitted {
+				t.Errorf("form is submitted")
+			}
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/large", strings.NewReader("_action=remove:Large.Loads"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status of not valid action %d", w.Code)
+	}
+}
+
+// testKey is key of signatures in tests
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// newCSRF return CSRF protection or fail test
+func newCSRF(t *testing.T, opts ...CSRFOption) CSRF {
+	t.Helper()
+	csrf, err := NewCSRF(testKey, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return csrf
+}
+
+func TestCSRF(t *testing.T) {
+	h := NewLargeHandler(func(ctx context.Context, l *Large) error {
+		return nil
+	}, WithCSRF(newCSRF(t)))
+
+	// token of form
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/large", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("cookies: %v", cookies)
+	}
+	index := strings.Index(w.Body.String(), `name="gencf_csrf" value="`)
+	if index < 0 {
+		t.Fatalf("html have not token:\n%s", w.Body.String())
+	}
+	token := w.Body.String()[index+len(`name="gencf_csrf" value="`):]
+	token = token[:strings.Index(token, `"`)]
+
+	form := url.Values{"Large.Name": {"model"}, "Large.Iterations": {"5"}}
+	for _, tc := range []struct {
+		name   string
+		cookie *http.Cookie
+		token  string
+		code   int
+	}{
+		{"without cookie", nil, token, http.StatusForbidden},
+		{"without token", cookies[0], "", http.StatusForbidden},
+		{"other cookie", &http.Cookie{Name: cookies[0].Name, Value: "other"}, token, http.StatusForbidden},
+		{"valid", cookies[0], token, http.StatusSeeOther},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := url.Values{"gencf_csrf": {tc.token}}
+			for k, v := range form {
+				f[k] = v
+			}
+			r := httptest.NewRequest(http.MethodPost, "/large", strings.NewReader(f.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.cookie != nil {
+				r.AddCookie(tc.cookie)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.code {
+				t.Errorf("status %d != %d", w.Code, tc.code)
+			}
+		})
+	}
+}
+
+// countReader is reader with amount of read bytes
+type countReader struct {
+	r    io.Reader
+	size int
+}
+
+func (c *countReader) Read(p []byte) (n int, err error) {
+	n, err = c.r.Read(p)
+	c.size += n
+	return
+}
+
+func TestCSRFMultipart(t *testing.T) {
+	h := NewLargeHandler(func(ctx context.Context, l *Large) error {
+		return nil
+	}, WithCSRF(newCSRF(t)))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/large", nil))
+	cookie := w.Result().Cookies()[0]
+	token := regexp.MustCompile(`name="gencf_csrf" value="([^"]*)"`).FindStringSubmatch(w.Body.String())[1]
+
+	for _, tc := range []struct {
+		name  string
+		token string
+		code  int
+	}{
+		{"valid", token, http.StatusSeeOther},
+		{"forged", "forged", http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			mw.WriteField("gencf_csrf", tc.token)
+			mw.WriteField("Large.Name", "model")
+			mw.WriteField("Large.Iterations", "1")
+			part, err := mw.CreateFormFile("Large.Other", "other.bin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			part.Write(bytes.Repeat([]byte("a"), 1<<20))
+			mw.Close()
+			size := body.Len()
+			c := &countReader{r: &body}
+			r := httptest.NewRequest(http.MethodPost, "/large", c)
+			r.Header.Set("Content-Type", mw.FormDataContentType())
+			r.AddCookie(cookie)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.code {
+				t.Fatalf("status %d != %d:\n%s", w.Code, tc.code, w.Body.String())
+			}
+			// files of forged request are not read
+			if tc.code == http.StatusForbidden && size/2 < c.size {
+				t.Errorf("read %d bytes of %d", c.size, size)
+			}
+		})
+	}
+}
+
+func TestCSRFSecure(t *testing.T) {
+	for _, tc := range []struct {
+		csrf   CSRF
+		secure bool
+	}{
+		{newCSRF(t), false},
+		{newCSRF(t, WithSecureCookie()), true},
+	} {
+		w := httptest.NewRecorder()
+		if _, _, err := tc.csrf.Token(w, httptest.NewRequest(http.MethodGet, "/large", nil)); err != nil {
+			t.Fatal(err)
+		}
+		if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].Secure != tc.secure {
+			t.Errorf("cookies: %v", cookies)
+		}
+	}
+}
+
+// middlewareCSRF is CSRF protection of external middleware
+type middlewareCSRF struct{}
+
+func (middlewareCSRF) Token(w http.ResponseWriter, r *http.Request) (name, value string, err error) {
+	return "middleware_token", "42", nil
+}
+
+func (middlewareCSRF) Verify(r *http.Request) error {
+	return nil
+}
+
+func TestCSRFKey(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("secret"), testKey[:31]} {
+		if _, err := NewCSRF(key); err == nil {
+			t.Errorf("short key of %d bytes is accepted", len(key))
+		}
+	}
+	if _, err := NewCSRF(testKey); err != nil {
+		t.Errorf("key of %d bytes is not accepted: %v", len(testKey), err)
+	}
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+	h := NewLargeHandler(func(ctx context.Context, l *Large) error {
+		return nil
+	}, WithCSRF(middlewareCSRF{}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/large", nil))
+	if exp := `<input type="hidden" name="middleware_token" value="42">`; !strings.Contains(w.Body.String(), exp) {
+		t.Errorf("html have not %q:\n%s", exp, w.Body.String())
+	}
+}
+
+func TestUpload(t *testing.T) {
+	var submitted *Large
+	h := NewLargeHandler(func(ctx context.Context, l *Large) error {
+		submitted = l
+		return nil
+	}, WithMaxSize(1024))
+
+	// form with uploaded files
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/large", nil))
+	for _, exp := range []string{
+		`enctype="multipart/form-data"`,
+		`<input type="file" id="gencf-Large-Report" name="Large.Report" accept="text/csv,.csv">`,
+	} {
+		if !strings.Contains(w.Body.String(), exp) {
+			t.Errorf("html have not %q:\n%s", exp, w.Body.String())
+		}
+	}
+
+	post := func(filename, contentType string, data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField("Large.Name", "model")
+		mw.WriteField("Large.Iterations", "1")
+		part, err := mw.CreatePart(map[string][]string{
+			"Content-Disposition": {`form-data; name="Large.Report"; filename="` + filename + `"`},
+			"Content-Type":        {contentType},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(data)
+		mw.Close()
+		r := httptest.NewRequest(http.MethodPost, "/large", &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		w := post("report.csv", "text/csv", []byte("a,b\n1,2\n"))
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("status %d:\n%s", w.Code, w.Body.String())
+		}
+		if submitted == nil || string(submitted.Report) != "a,b\n1,2\n" || submitted.Name != "model" {
+			t.Errorf("submitted value: %#v", submitted)
+		}
+	})
+	for _, tc := range []struct {
+		name        string
+		filename    string
+		contentType string
+		data        []byte
+		code        int
+		exp         string
+	}{
+		{"extension", "report.CSV", "application/octet-stream", []byte("a"), http.StatusSeeOther, ""},
+		{"type", "report.png", "image/png", []byte("a"), http.StatusUnprocessableEntity, "file type is not allowed"},
+		{"content html", "report.csv", "text/csv", []byte("<html><script>alert(1)</script>"), http.StatusUnprocessableEntity, "file type is not allowed"},
+		{"content png", "report.csv", "text/csv", []byte("\x89PNG\r\n\x1a\n"), http.StatusUnprocessableEntity, "file type is not allowed"},
+		{"size", "report.csv", "text/csv", bytes.Repeat([]byte("a"), 100), http.StatusUnprocessableEntity, "file size must be at most 64 bytes"},
+		{"request size", "report.csv", "text/csv", bytes.Repeat([]byte("a"), 2000), http.StatusBadRequest, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := post(tc.filename, tc.contentType, tc.data)
+			if w.Code != tc.code {
+				t.Fatalf("status %d:\n%s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tc.exp) {
+				t.Errorf("html have not %q:\n%s", tc.exp, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUploadLimit(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("Report", "report.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(bytes.Repeat([]byte("a"), 1000))
+	mw.Close()
+	form, err := multipart.NewReader(&body, mw.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// content of file is read only up to limit
+	errs := FormErrors{}
+	if file := gencfFile(form.File, "Report", nil, 64, errs); len(file.Data) != 65 || len(errs) != 0 {
+		t.Errorf("file size is %d, errors: %v", len(file.Data), errs)
+	}
+	if file := gencfFile(form.File, "Report", nil, -1, errs); len(file.Data) != 1000 || len(errs) != 0 {
+		t.Errorf("file size is %d, errors: %v", len(file.Data), errs)
+	}
+}
+
+func BenchmarkWriteHtml(b *testing.B) {
+	for _, size := range []int{10, 100, 1000} {
+		l := large(size)
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := l.WriteHtml(ioutil.Discard); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkToHtml(b *testing.B) {
+	for _, size := range []int{10, 100, 1000} {
+		l := large(size)
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = l.ToHtml()
+			}
+		})
+	}
+}
+
+// BenchmarkConcat is html by string concatenation in code generated
+// before streaming
+func BenchmarkConcat(b *testing.B) {
+	for _, size := range []int{10, 100, 1000} {
+		c := concat(size)
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = c.ToHtml()
+			}
+		})
+	}
+}
+
+// sessionStore is store of wizard values on server side
+type sessionStore struct {
+	sessions []url.Values
+}
+
+func (s *sessionStore) Save(w http.ResponseWriter, r *http.Request, values url.Values) (string, error) {
+	s.sessions = append(s.sessions, values)
+	return fmt.Sprint(len(s.sessions) - 1), nil
+}
+
+func (s *sessionStore) Load(r *http.Request, token string) (url.Values, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || len(s.sessions) <= i {
+		return nil, fmt.Errorf("session is not found")
+	}
+	return s.sessions[i], nil
+}
+
+func TestWizard(t *testing.T) {
+	signed, err := NewSignedStore(testKey, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, store := range map[string]WizardStore{
+		"signed":  signed,
+		"session": &sessionStore{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var submitted *Account
+			h := NewAccountWizard(store, func(ctx context.Context, a *Account) error {
+				submitted = a
+				return nil
+			})
+
+			// state is hidden input of previous page
+			var state string
+			page := func(w *httptest.ResponseRecorder, code int, exp ...string) {
+				t.Helper()
+				if w.Code != code {
+					t.Fatalf("status %d != %d:\n%s", w.Code, code, w.Body.String())
+				}
+				for _, e := range exp {
+					if !strings.Contains(w.Body.String(), e) {
+						t.Errorf("html have not %q:\n%s", e, w.Body.String())
+					}
+				}
+				if m := regexp.MustCompile(`name="_state" value="([^"]*)"`).FindStringSubmatch(w.Body.String()); m != nil {
+					state = m[1]
+				}
+			}
+			post := func(form url.Values) *httptest.ResponseRecorder {
+				form.Set("_state", state)
+				r := httptest.NewRequest(http.MethodPost, "/account", strings.NewReader(form.Encode()))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				return w
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/account", nil))
+			page(w, http.StatusOK, `name="Account.Name"`, `aria-current="step"`, `value="Next"`)
+			if strings.Contains(w.Body.String(), `name="Account.Email"`) {
+				t.Errorf("fields of next step are shown:\n%s", w.Body.String())
+			}
+
+			page(post(url.Values{}), http.StatusUnprocessableEntity, "value is required")
+			page(post(url.Values{"Account.Name": {"bob"}, "Account.Email": {"other"}}),
+				http.StatusOK, `name="Account.Email"`, `name="Account.Phone"`, `value="back"`)
+			page(post(url.Values{"_wizard": {"back"}}), http.StatusOK, `name="Account.Name" value="bob"`)
+			page(post(url.Values{"Account.Name": {"alice"}}), http.StatusOK, `name="Account.Email" value=""`)
+			page(post(url.Values{"Account.Phone": {"123"}}), http.StatusUnprocessableEntity,
+				`name="Account.Phone" value="123"`, "value is required")
+			page(post(url.Values{"Account.Email": {"a@b.c"}}), http.StatusOK, `name="Account.Newsletter"`)
+			page(post(url.Values{"Account.Newsletter": {"true"}}), http.StatusOK, "disabled",
+				`value="Submit"`, `name="Account.Name" value="alice"`, `name="Account.Email" value="a@b.c"`,
+				`name="_wizard" value="2"`)
+			review := state
+
+			// change of previous step from review
+			page(post(url.Values{"_wizard": {"1"}}), http.StatusOK, `name="Account.Phone" value=""`)
+			state = review
+			if w := post(url.Values{"_wizard": {"5"}}); w.Code != http.StatusBadRequest {
+				t.Errorf("step after current step: status %d", w.Code)
+			}
+			state = review + "x"
+			if w := post(url.Values{}); w.Code != http.StatusBadRequest {
+				t.Errorf("not valid state: status %d", w.Code)
+			}
+
+			state = review
+			if w := post(url.Values{}); w.Code != http.StatusSeeOther {
+				t.Fatalf("status %d:\n%s", w.Code, w.Body.String())
+			}
+			exp := Account{Name: "alice", Email: "a@b.c", Newsletter: true}
+			if submitted == nil || *submitted != exp {
+				t.Errorf("submitted value: %#v", submitted)
+			}
+		})
+	}
+}
+
+func TestSignedStoreKey(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("secret"), testKey[:31]} {
+		if _, err := NewSignedStore(key, time.Hour); err == nil {
+			t.Errorf("short key of %d bytes is accepted", len(key))
+		}
+	}
+	if _, err := NewSignedStore(testKey, time.Hour); err != nil {
+		t.Errorf("key of %d bytes is not accepted: %v", len(testKey), err)
+	}
+}
+
+func TestSignedStoreExpiration(t *testing.T) {
+	defer func() {
+		gencfNow = time.Now
+	}()
+	now := time.Now()
+	gencfNow = func() time.Time { return now }
+
+	store, err := NewSignedStore(testKey, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := url.Values{"Account.Name": {"alice"}}
+	token, err := store.Save(nil, nil, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, err := store.Load(nil, token); err != nil || !reflect.DeepEqual(act, values) {
+		t.Fatalf("values %v: %v", act, err)
+	}
+
+	// expiration time is signed
+	expires := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+	later := strconv.FormatInt(now.Add(time.Hour).Unix(), 10)
+	if _, err := store.Load(nil, strings.Replace(token, expires, later, 1)); err == nil {
+		t.Errorf("changed expiration time is accepted")
+	}
+
+	gencfNow = func() time.Time { return now.Add(2 * time.Minute) }
+	if _, err := store.Load(nil, token); err == nil {
+		t.Errorf("expired token is accepted")
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// amount of unchanged lines around each change in unified diff
+const diffContext = 3
+
+// unifiedDiff return difference between `a` and `b` in unified format.
+// Empty string is returned for equal inputs.
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	d := differ{a: splitLines(string(a)), b: splitLines(string(b))}
+	d.compare(0, len(d.a), 0, len(d.b))
+	edits := d.script()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", aName, bName)
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			k++
+			continue
+		}
+		// borders of hunk
+		start := k - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := k
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			// look for next change in context range
+			next := end
+			for next < len(edits) && edits[next].op == ' ' && next-end < 2*diffContext {
+				next++
+			}
+			if next < len(edits) && edits[next].op != ' ' {
+				end = next
+				continue
+			}
+			break
+		}
+		stop := end + diffContext
+		if stop > len(edits) {
+			stop = len(edits)
+		}
+
+		var aCount, bCount int
+		for _, e := range edits[start:stop] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(edits[start].ai, aCount),
+			hunkRange(edits[start].bi, bCount))
+		for _, e := range edits[start:stop] {
+			fmt.Fprintf(&buf, "%c%s\n", e.op, e.line)
+		}
+		k = stop
+	}
+	return buf.String()
+}
+
+// edit is line of edit script
+type edit struct {
+	op   byte // ' ', '-', '+'
+	line string
+	ai   int // index of line in `a`
+	bi   int // index of line in `b`
+}
+
+// differ is state of linear space Myers algorithm of difference
+// between lines `a` and `b`
+type differ struct {
+	a, b  []string
+	edits []edit
+}
+
+// compare append edits of lines a[a0:a1] and b[b0:b1]
+func (d *differ) compare(a0, a1, b0, b1 int) {
+	// common prefix and suffix
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		d.edits = append(d.edits, edit{op: ' ', line: d.a[a0]})
+		a0++
+		b0++
+	}
+	suffix := 0
+	for a0 < a1-suffix && b0 < b1-suffix && d.a[a1-1-suffix] == d.b[b1-1-suffix] {
+		suffix++
+	}
+	a1 -= suffix
+	b1 -= suffix
+
+	switch {
+	case a0 == a1:
+		for j := b0; j < b1; j++ {
+			d.edits = append(d.edits, edit{op: '+', line: d.b[j]})
+		}
+	case b0 == b1:
+		for i := a0; i < a1; i++ {
+			d.edits = append(d.edits, edit{op: '-', line: d.a[i]})
+		}
+	default:
+		x, y, u, v := d.middle(a0, a1, b0, b1)
+		d.compare(a0, x, b0, y)
+		for ; x < u; x++ {
+			d.edits = append(d.edits, edit{op: ' ', line: d.a[x]})
+		}
+		d.compare(u, a1, v, b1)
+	}
+
+	for k := 0; k < suffix; k++ {
+		d.edits = append(d.edits, edit{op: ' ', line: d.a[a1+k]})
+	}
+}
+
+// script return edits with indexes of lines. In each change removed
+// lines are before added lines.
+func (d *differ) script() (edits []edit) {
+	i, j := 0, 0
+	for k := 0; k < len(d.edits); {
+		if d.edits[k].op == ' ' {
+			edits = append(edits, edit{' ', d.edits[k].line, i, j})
+			i++
+			j++
+			k++
+			continue
+		}
+		end := k
+		for end < len(d.edits) && d.edits[end].op != ' ' {
+			end++
+		}
+		for _, op := range []byte{'-', '+'} {
+			for _, e := range d.edits[k:end] {
+				if e.op != op {
+					continue
+				}
+				edits = append(edits, edit{op, e.line, i, j})
+				if op == '-' {
+					i++
+				} else {
+					j++
+				}
+			}
+		}
+		k = end
+	}
+	return
+}
+
+// middle return middle snake from (x, y) to (u, v) of shortest edit
+// script of lines a[a0:a1] and b[b0:b1]. Memory is linear.
+func (d *differ) middle(a0, a1, b0, b1 int) (x, y, u, v int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+
+	// furthest x on diagonals k = x - y of forward and backward paths.
+	// Backward path is in coordinates from ends of lines.
+	offset := max + 1
+	forward := make([]int, 2*max+3)
+	backward := make([]int, 2*max+3)
+
+	for step := 0; step <= max; step++ {
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.a[a0+x] == d.b[b0+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if kb := delta - k; odd && -step < kb && kb < step && x+backward[offset+kb] >= n {
+				return a0 + sx, b0 + sy, a0 + x, b0 + y
+			}
+		}
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || (k != step && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.a[a1-1-x] == d.b[b1-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if kf := delta - k; !odd && -step <= kf && kf <= step && x+forward[offset+kf] >= n {
+				return a1 - x, b1 - y, a1 - sx, b1 - sy
+			}
+		}
+	}
+	// not reachable: paths are overlapped before
+	return a0, b0, a0, b0
+}
+
+func hunkRange(index, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", index)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", index+1)
+	}
+	return fmt.Sprintf("%d,%d", index+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
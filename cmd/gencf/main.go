@@ -0,0 +1,313 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Konstantin8105/errors"
+	"github.com/Konstantin8105/gencf"
+)
+
+// params is parameters of command line
+type params struct {
+	gencf.Config
+
+	OutputFilename string
+
+	// Check is true for compare generated source with present output
+	// file without any changes on disk
+	Check bool
+
+	// Diff is true for print difference between present output file
+	// and generated source without any changes on disk
+	Diff bool
+
+	// Verbose is true for print information about generation
+	Verbose bool
+
+	// DumpIR is true for print intermediate representation of structs
+	// in JSON format
+	DumpIR bool
+
+	// ExtractMessages is true for print source catalog of messages
+	// in JSON format
+	ExtractMessages bool
+}
+
+// stdoutFilename is name of output file for write generated source
+// into `osStdout`
+const stdoutFilename = "-"
+
+// pipe for generated source and difference
+var osStdout io.Writer = os.Stdout
+
+// pipe for outpur information
+var osStderr io.Writer = os.Stderr
+
+type arrayStrings []string
+
+func (a *arrayStrings) String() string {
+	return fmt.Sprintf("%v", []string(*a))
+}
+
+func (a *arrayStrings) Set(value string) error {
+	v := []string(*a)
+	v = append(v, value)
+	*a = arrayStrings(v)
+	return nil
+}
+
+func main() {
+	// CLI design
+	// gensf -struct=foo -struct=buz -o=out_file.go -i=file1.go -i=file2.go
+	//
+	// check output file is up to date, for example in CI:
+	// gensf -check -struct=foo -o=out_file.go -i=file1.go
+	//
+	// write generated source to stdout:
+	// gensf -struct=foo -o=- -i=file1.go
+	//
+	// show difference between output file and generated source:
+	// gensf -diff -struct=foo -o=out_file.go -i=file1.go
+	//
+	// print intermediate representation of structs for debugging:
+	// gensf -dump-ir -struct=foo -i=file1.go
+	//
+	// print source catalog of labels, help and messages for translation:
+	// gensf -extract-messages -struct=foo -i=file1.go > messages.en.json
+	//
+	// generate html templates near output file:
+	// gensf -mode=template -struct=foo -o=out_file.go -i=file1.go
+	//
+	// generate JSON Schema near output file:
+	// gensf -schema -struct=foo -o=out_file.go -i=file1.go
+	//
+	// generate OpenAPI document near output file:
+	// gensf -openapi=yaml -struct=foo -o=out_file.go -i=file1.go
+	//
+	// generate TypeScript interfaces and form data functions:
+	// gensf -ts=web/forms.ts -struct=foo -o=out_file.go -i=file1.go
+	//
+	// replace html templates of widgets by templates from folder:
+	// gensf -templates=widgets -struct=foo -o=out_file.go -i=file1.go
+	//
+	// generate html markup for CSS framework:
+	// gensf -theme=bootstrap5 -struct=foo -o=out_file.go -i=file1.go
+	//
+	// generate runtime into output file instead of 'gencf_gen.go':
+	// gensf -embed-runtime -struct=foo -o=out_file.go -i=file1.go
+
+	var p params
+
+	// flags
+	var pif, pst arrayStrings
+
+	flag.Var(&pif, "i", "input filename for example : 'main.go'")
+	flag.Var(&pst, "struct", "name of struct")
+	flag.StringVar(&p.OutputFilename, "o", "out_gen.go", "name of output filename, '-' for stdout")
+	flag.StringVar(&p.PackageName, "p", "main", "package in generate file")
+	flag.StringVar((*string)(&p.Mode), "mode", string(gencf.ModeCode), "mode of generation: 'code' or 'template'")
+	flag.StringVar(&p.Theme, "theme", gencf.ThemePlain, fmt.Sprintf("theme of html markup: %s", strings.Join(gencf.Themes(), ", ")))
+	flag.StringVar(&p.TemplatesDir, "templates", "", "folder with html templates of widgets '*.tmpl' for replace templates of theme")
+	flag.BoolVar(&p.Schema, "schema", false, "generate JSON Schema file '<Struct>.schema.json' near output file for each struct")
+	flag.StringVar((*string)(&p.OpenAPI), "openapi", "", "generate OpenAPI 3.1 document 'openapi.<format>' near output file: 'json' or 'yaml'")
+	flag.StringVar(&p.TypeScript, "ts", "", "generate TypeScript file with interfaces and form data functions near output file, for example: 'forms.ts'")
+	flag.StringVar(&p.Runtime, "runtime", gencf.RuntimeFilename, "name of file with runtime near output file, which is shared by several output files in one package")
+	flag.BoolVar(&p.EmbedRuntime, "embed-runtime", false, "generate only used parts of runtime into output file, which must be only one generated file in package")
+	flag.BoolVar(&p.Check, "check", false, "check output file is up to date, without write")
+	flag.BoolVar(&p.Diff, "diff", false, "show difference with output file, without write")
+	flag.BoolVar(&p.Verbose, "v", false, "print information about generation")
+	flag.BoolVar(&p.DumpIR, "dump-ir", false, "print intermediate representation of structs in JSON, without write")
+	flag.BoolVar(&p.ExtractMessages, "extract-messages", false, "print source catalog of messages in JSON, without write")
+	flag.Parse()
+
+	p.InputFilename = []string(pif)
+	p.Structs = []string(pst)
+
+	// run parsing
+	err := run(p)
+	if err != nil {
+		fmt.Fprintf(osStderr, "%v\n", err)
+		os.Exit(-1)
+	}
+}
+
+func run(p params) error {
+	// check input data
+	et := errors.New("Check input data")
+	if p.OutputFilename == "" {
+		et.Add(fmt.Errorf("name of output file is empty"))
+	}
+	if p.OutputFilename == stdoutFilename && (p.Check || p.Diff) {
+		et.Add(fmt.Errorf("check and diff is not allowable for stdout output"))
+	}
+	if p.OutputFilename == stdoutFilename && p.Mode == gencf.ModeTemplate {
+		et.Add(fmt.Errorf("template mode is not allowable for stdout output"))
+	}
+	if p.OutputFilename == stdoutFilename && p.Schema {
+		et.Add(fmt.Errorf("JSON Schema is not allowable for stdout output"))
+	}
+	if p.OutputFilename == stdoutFilename && p.OpenAPI != "" {
+		et.Add(fmt.Errorf("OpenAPI document is not allowable for stdout output"))
+	}
+	if p.OutputFilename == stdoutFilename && p.TypeScript != "" {
+		et.Add(fmt.Errorf("TypeScript file is not allowable for stdout output"))
+	}
+	if p.Runtime == "" {
+		p.Runtime = gencf.RuntimeFilename
+	}
+	if !p.EmbedRuntime && p.OutputFilename != stdoutFilename &&
+		filepath.Base(p.OutputFilename) == p.Runtime {
+		et.Add(fmt.Errorf("name of output file is same as name of runtime file"))
+	}
+	if p.Check && p.Diff {
+		et.Add(fmt.Errorf("check and diff cannot be used together"))
+	}
+	if et.IsError() {
+		flag.PrintDefaults()
+		return et
+	}
+
+	// print input data
+	if p.Verbose {
+		fmt.Fprintf(osStderr, "Generate HTML form from Go struct:\n")
+		fmt.Fprintf(osStderr, "Package name: %s\n", p.PackageName)
+		fmt.Fprintf(osStderr, "Input go files:\n")
+		for i := range p.InputFilename {
+			fmt.Fprintf(osStderr, "\t* %s\n", p.InputFilename[i])
+		}
+		fmt.Fprintf(osStderr, "Parsing next Go structs:\n")
+		for i := range p.Structs {
+			fmt.Fprintf(osStderr, "\t* %s\n", p.Structs[i])
+		}
+		fmt.Fprintf(osStderr, "Output go file: %s\n", p.OutputFilename)
+	}
+
+	// warnings
+	p.Log = osStderr
+
+	if p.OutputFilename == stdoutFilename {
+		// only one file is written into stdout
+		p.EmbedRuntime = true
+	}
+
+	if p.DumpIR {
+		return dumpIR(p)
+	}
+	if p.ExtractMessages {
+		return extractMessages(p)
+	}
+
+	// generated files
+	files, err := gencf.GenerateFiles(p.Config)
+	if err != nil {
+		return err
+	}
+	for i := range files {
+		files[i].Name = outputFilename(p, files[i])
+	}
+
+	switch {
+	case p.Check:
+		return check(files)
+	case p.Diff:
+		return diff(files)
+	case p.OutputFilename == stdoutFilename:
+		_, err = osStdout.Write(files[0].Data)
+		return err
+	}
+
+	for _, f := range files {
+		// save structs into output file
+		if _, err := os.Stat(f.Name); err == nil {
+			err = os.Remove(f.Name)
+			if err != nil {
+				return fmt.Errorf("cannot remove file : %v", err)
+			}
+		}
+
+		if err := ioutil.WriteFile(f.Name, f.Data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outputFilename return name of generated file on disk
+func outputFilename(p params, f gencf.OutputFile) string {
+	if f.Name == "" {
+		return p.OutputFilename
+	}
+	return filepath.Join(filepath.Dir(p.OutputFilename), f.Name)
+}
+
+// dumpIR print intermediate representation of structs
+func dumpIR(p params) error {
+	forms, err := gencf.Parse(p.Config)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(forms, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(osStdout, "%s\n", b)
+	return err
+}
+
+// extractMessages print source catalog of messages
+func extractMessages(p params) error {
+	messages, err := gencf.ExtractMessages(p.Config)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(messages, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(osStdout, "%s\n", b)
+	return err
+}
+
+// check compare generated files with present files
+func check(files []gencf.OutputFile) error {
+	et := errors.New("Check output files")
+	for _, f := range files {
+		present, err := ioutil.ReadFile(f.Name)
+		if err != nil {
+			et.Add(fmt.Errorf("cannot read output file : %v", err))
+			continue
+		}
+		if diff := unifiedDiff(f.Name, f.Name+" (generated)", present, f.Data); diff != "" {
+			et.Add(fmt.Errorf("output file `%s` is not up to date:\n%s",
+				f.Name, diff))
+		}
+	}
+	if et.IsError() {
+		return et
+	}
+	return nil
+}
+
+// diff print difference between present files and generated files.
+// Not exist file is compared as empty.
+func diff(files []gencf.OutputFile) error {
+	for _, f := range files {
+		present, err := ioutil.ReadFile(f.Name)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot read output file : %v", err)
+		}
+		_, err = fmt.Fprint(osStdout,
+			unifiedDiff(f.Name, f.Name+" (generated)", present, f.Data))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,518 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Konstantin8105/gencf"
+)
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/6.got")}
+	p.OutputFilename = filepath.Join(dir, "6.gen.go")
+	p.Structs = []string{"TestStruct"}
+
+	// output file is not exist
+	p.Check = true
+	if err := run(p); err == nil {
+		t.Fatalf("check without output file must fail")
+	}
+
+	// generate
+	p.Check = false
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+
+	// output file is up to date
+	p.Check = true
+	if err := run(p); err != nil {
+		t.Fatalf("check of fresh file: %v", err)
+	}
+
+	// output file is changed
+	b, err := ioutil.ReadFile(p.OutputFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := bytes.Replace(b, []byte("S is slice"), []byte("S is old slice"), 1)
+	if err := ioutil.WriteFile(p.OutputFilename, changed, 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = run(p)
+	if err == nil {
+		t.Fatalf("check of changed file must fail")
+	}
+	for _, line := range []string{
+		"-\t\t\tLabel:  gencfText(opts.Locale, \"TestStruct.S\", \"S is old slice\"),",
+		"+\t\t\tLabel:  gencfText(opts.Locale, \"TestStruct.S\", \"S is slice\"),",
+	} {
+		if !strings.Contains(err.Error(), line) {
+			t.Errorf("diff have not line %q:\n%v", line, err)
+		}
+	}
+
+	// check mode must not change the output file
+	after, err := ioutil.ReadFile(p.OutputFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(changed, after) {
+		t.Errorf("output file is changed in check mode")
+	}
+}
+
+func TestStdout(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	osStdout, osStderr = &stdout, &stderr
+	defer func() {
+		osStdout, osStderr = os.Stdout, os.Stderr
+	}()
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/6.got")}
+	p.OutputFilename = "-"
+	p.Structs = []string{"TestStruct"}
+
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+
+	bExp, err := ioutil.ReadFile(filepath.FromSlash("../../testdata/6.gen.got.expected"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stdout.Bytes(), bExp) {
+		t.Errorf("%s", unifiedDiff("actual", "expected", stdout.Bytes(), bExp))
+	}
+	if _, err := os.Stat("-"); err == nil {
+		t.Errorf("file `-` is created")
+	}
+	if strings.Contains(stderr.String(), "Generate HTML form") {
+		t.Errorf("information printed without verbose flag:\n%s", stderr.String())
+	}
+
+	// verbose
+	stdout.Reset()
+	p.Verbose = true
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stdout.Bytes(), bExp) {
+		t.Errorf("verbose output is changed:\n%s", unifiedDiff("actual", "expected", stdout.Bytes(), bExp))
+	}
+	if !strings.Contains(stderr.String(), "Generate HTML form") {
+		t.Errorf("information is not printed in verbose mode")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	var stdout bytes.Buffer
+	osStdout = &stdout
+	defer func() {
+		osStdout = os.Stdout
+	}()
+
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/6.got")}
+	p.OutputFilename = filepath.Join(dir, "6.gen.go")
+	p.Structs = []string{"TestStruct"}
+	p.Diff = true
+
+	// output file is not exist
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout.String(), "+func (value TestStruct) ToHtml() (out string) {") {
+		t.Errorf("diff with not exist file:\n%s", stdout.String())
+	}
+	if _, err := os.Stat(p.OutputFilename); err == nil {
+		t.Errorf("output file is created in diff mode")
+	}
+
+	// output file is up to date
+	p.Diff = false
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	p.Diff = true
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("diff of fresh file is not empty:\n%s", stdout.String())
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	exp := `--- a
++++ b
+@@ -1,7 +1,6 @@
+ x
+-1
+-2
+-3
++4
++5
+ y
+-p
+ q
++r
+`
+	a := []byte("x\n1\n2\n3\ny\np\nq\n")
+	b := []byte("x\n4\n5\ny\nq\nr\n")
+	if act := unifiedDiff("a", "b", a, b); act != exp {
+		t.Errorf("not valid diff:\n%s", act)
+	}
+
+	// edit script is shortest
+	r := rand.New(rand.NewSource(1))
+	random := func() (lines []string) {
+		for i := r.Intn(20); i > 0; i-- {
+			lines = append(lines, fmt.Sprint(r.Intn(4)))
+		}
+		return
+	}
+	for i := 0; i < 1000; i++ {
+		d := differ{a: random(), b: random()}
+		d.compare(0, len(d.a), 0, len(d.b))
+		var changes int
+		var a, b []string
+		for _, e := range d.script() {
+			if e.op != '+' {
+				a = append(a, e.line)
+			}
+			if e.op != '-' {
+				b = append(b, e.line)
+			}
+			if e.op != ' ' {
+				changes++
+			}
+		}
+		if strings.Join(a, "\n") != strings.Join(d.a, "\n") || strings.Join(b, "\n") != strings.Join(d.b, "\n") {
+			t.Fatalf("not valid edit script of %q and %q", d.a, d.b)
+		}
+		if exp := len(d.a) + len(d.b) - 2*lcs(d.a, d.b); changes != exp {
+			t.Fatalf("edit script of %q and %q have %d changes instead of %d", d.a, d.b, changes, exp)
+		}
+	}
+
+	// large files with few changes
+	var large bytes.Buffer
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&large, "line %d\n", i)
+	}
+	changed := bytes.Replace(large.Bytes(), []byte("line 50000\n"), []byte("changed\n"), 1)
+	if act := unifiedDiff("a", "b", large.Bytes(), changed); !strings.Contains(act, "-line 50000\n+changed\n") {
+		t.Errorf("not valid diff of large files:\n%s", act)
+	}
+}
+
+// lcs return length of longest common subsequence of lines
+func lcs(a, b []string) int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				table[i][j] = table[i+1][j+1] + 1
+			case table[i+1][j] >= table[i][j+1]:
+				table[i][j] = table[i+1][j]
+			default:
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+	return table[0][0]
+}
+
+func TestDumpIR(t *testing.T) {
+	var stdout bytes.Buffer
+	osStdout = &stdout
+	defer func() {
+		osStdout = os.Stdout
+	}()
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/5.got")}
+	p.OutputFilename = "-"
+	p.Structs = []string{"TestStruct", "Se"}
+	p.DumpIR = true
+
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	var forms []gencf.Form
+	if err := json.Unmarshal(stdout.Bytes(), &forms); err != nil {
+		t.Fatalf("%v:\n%s", err, stdout.String())
+	}
+	if len(forms) != 2 || forms[0].Name != "Se" || forms[1].Name != "TestStruct" {
+		t.Fatalf("not valid forms:\n%s", stdout.String())
+	}
+	if f := forms[0].Fields[1].Fields[0]; f.Kind != gencf.KindBasic || f.Path != "r.o" {
+		t.Errorf("not valid field: %#v", f)
+	}
+}
+
+func TestExtractMessages(t *testing.T) {
+	var stdout bytes.Buffer
+	osStdout = &stdout
+	defer func() {
+		osStdout = os.Stdout
+	}()
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/8.got")}
+	p.OutputFilename = "-"
+	p.Structs = []string{"TestStruct"}
+	p.ExtractMessages = true
+
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	var messages map[string]string
+	if err := json.Unmarshal(stdout.Bytes(), &messages); err != nil {
+		t.Fatalf("%v:\n%s", err, stdout.String())
+	}
+	if messages["TestStruct.Age"] != "Age of person" {
+		t.Errorf("not valid messages:\n%s", stdout.String())
+	}
+}
+
+func TestTemplateMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/5.got")}
+	p.OutputFilename = filepath.Join(dir, "5.gen.go")
+	p.Structs = []string{"TestStruct", "Se"}
+	p.Mode = gencf.ModeTemplate
+
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"5.gen.go", "Se.gen.tmpl", "TestStruct.gen.tmpl"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("file is not generated: %v", err)
+		}
+	}
+
+	// templates are checked
+	p.Check = true
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "Se.gen.tmpl")); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(p); err == nil || !strings.Contains(err.Error(), "Se.gen.tmpl") {
+		t.Errorf("check without template: %v", err)
+	}
+}
+
+func TestRuntime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/5.got")}
+
+	// two output files in one package share runtime file
+	for output, structs := range map[string]string{"t_gen.go": "TestStruct", "se_gen.go": "Se"} {
+		p.OutputFilename = filepath.Join(dir, output)
+		p.Structs = []string{structs}
+		if err := run(p); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(p.OutputFilename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(b, []byte("func gencfWrite(")) {
+			t.Errorf("runtime is generated in %s", output)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, gencf.RuntimeFilename)); err != nil {
+		t.Errorf("runtime file is not generated: %v", err)
+	}
+
+	// output file with runtime
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	p.EmbedRuntime = true
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, gencf.RuntimeFilename)); err == nil {
+		t.Errorf("runtime file is generated")
+	}
+
+	// output file is runtime file
+	p.EmbedRuntime = false
+	p.OutputFilename = filepath.Join(dir, gencf.RuntimeFilename)
+	if err := run(p); err == nil {
+		t.Errorf("output file is same as runtime file")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/8.got")}
+	p.OutputFilename = filepath.Join(dir, "8.gen.go")
+	p.Structs = []string{"TestStruct"}
+	p.Schema = true
+
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "TestStruct.schema.json")); err != nil {
+		t.Errorf("file is not generated: %v", err)
+	}
+
+	// JSON Schema is not written into stdout
+	p.OutputFilename = stdoutFilename
+	if err := run(p); err == nil {
+		t.Errorf("JSON Schema for stdout output must be error")
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/9.got")}
+	p.OutputFilename = filepath.Join(dir, "9.gen.go")
+	p.Structs = []string{"TestStruct"}
+	p.OpenAPI = gencf.FormatJSON
+
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "openapi.json"))
+	if err != nil {
+		t.Fatalf("file is not generated: %v", err)
+	}
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			RequestBodies map[string]struct {
+				Content map[string]json.RawMessage `json:"content"`
+			} `json:"requestBodies"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("not valid version: %s", doc.OpenAPI)
+	}
+	if _, ok := doc.Components.RequestBodies["TestStruct"].Content["multipart/form-data"]; !ok {
+		t.Errorf("request body with files must be multipart")
+	}
+
+	// not valid format
+	p.OpenAPI = "xml"
+	if err := run(p); err == nil {
+		t.Errorf("not valid format must be error")
+	}
+
+	// OpenAPI document is not written into stdout
+	p.OpenAPI = gencf.FormatYAML
+	p.OutputFilename = stdoutFilename
+	if err := run(p); err == nil {
+		t.Errorf("OpenAPI document for stdout output must be error")
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p params
+	p.PackageName = "main"
+	p.InputFilename = []string{filepath.FromSlash("../../testdata/7.got")}
+	p.OutputFilename = filepath.Join(dir, "7.gen.go")
+	p.Structs = []string{"TestStruct", "Se"}
+	// name is relative to folder of output file
+	p.TypeScript = filepath.Join("web", "forms.ts")
+
+	if err := os.Mkdir(filepath.Join(dir, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(p.TypeScript); err == nil {
+		t.Errorf("file is generated relative to working folder")
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "web", "forms.ts"))
+	if err != nil {
+		t.Fatalf("file is not generated: %v", err)
+	}
+	if !strings.Contains(string(b), "export interface TestStruct {") {
+		t.Errorf("interface is not generated:\n%s", b)
+	}
+
+	// TypeScript file is not written into stdout
+	p.OutputFilename = stdoutFilename
+	if err := run(p); err == nil {
+		t.Errorf("TypeScript file for stdout output must be error")
+	}
+}
@@ -0,0 +1,108 @@
+package gencf
+
+// csrfRuntime is Go source of types and functions of protection against
+// cross-site request forgery
+const csrfRuntime = `
+// CSRF is protection of form against cross-site request forgery.
+// Existing CSRF middleware may be used by implementation of interface.
+type CSRF interface {
+	// Token return name and value of hidden html input with token
+	Token(w http.ResponseWriter, r *http.Request) (name, value string, err error)
+
+	// Verify return error for request with not valid token.
+	// Form values of request are parsed.
+	Verify(r *http.Request) error
+}
+
+// NewCSRF return protection by double-submit cookie with random value
+// and token signed by key (HMAC-SHA256). Key is secret random bytes with
+// length at least 32 bytes, for shorter key error is returned.
+func NewCSRF(key []byte, opts ...CSRFOption) (CSRF, error) {
+	if err := gencfCheckKey("NewCSRF", key); err != nil {
+		return nil, err
+	}
+	c := gencfCSRF{key: key}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c, nil
+}
+
+// CSRFOption is option of protection by NewCSRF
+type CSRFOption func(c *gencfCSRF)
+
+// WithSecureCookie return option for set attribute Secure of cookie for
+// all requests. By default attribute is set only for requests by TLS,
+// so for server behind TLS proxy option must be used.
+func WithSecureCookie() CSRFOption {
+	return func(c *gencfCSRF) {
+		c.secure = true
+	}
+}
+
+// gencfKeySize is minimal length of key of signature in bytes
+const gencfKeySize = 32
+
+// gencfCheckKey return error for key of signature shorter than
+// gencfKeySize
+func gencfCheckKey(name string, key []byte) error {
+	if len(key) < gencfKeySize {
+		return fmt.Errorf("%s: key must have at least %d bytes, but have %d bytes",
+			name, gencfKeySize, len(key))
+	}
+	return nil
+}
+
+// gencfCSRFName is name of cookie and html input with token
+const gencfCSRFName = "gencf_csrf"
+
+// gencfCSRF is protection by double-submit cookie
+type gencfCSRF struct {
+	key    []byte
+	secure bool
+}
+
+func (c gencfCSRF) Token(w http.ResponseWriter, r *http.Request) (name, value string, err error) {
+	cookie, err := r.Cookie(gencfCSRFName)
+	if err != nil || cookie.Value == "" {
+		b := make([]byte, 32)
+		if _, err = rand.Read(b); err != nil {
+			return
+		}
+		cookie = &http.Cookie{
+			Name:     gencfCSRFName,
+			Value:    base64.RawURLEncoding.EncodeToString(b),
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   c.secure || r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		}
+		http.SetCookie(w, cookie)
+	}
+	return gencfCSRFName, c.sign(cookie.Value), nil
+}
+
+func (c gencfCSRF) Verify(r *http.Request) error {
+	cookie, err := r.Cookie(gencfCSRFName)
+	if err != nil {
+		return fmt.Errorf("CSRF cookie is not found")
+	}
+	token := r.PostForm.Get(gencfCSRFName)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(c.sign(cookie.Value))) != 1 {
+		return fmt.Errorf("CSRF token is not valid")
+	}
+	return nil
+}
+
+// sign return signature of value
+func (c gencfCSRF) sign(value string) string {
+	return gencfSign(c.key, value)
+}
+
+// gencfSign return signature of value by key (HMAC-SHA256)
+func gencfSign(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+`
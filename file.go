@@ -0,0 +1,133 @@
+package gencf
+
+import (
+	"bytes"
+	"io"
+)
+
+// File is uploaded file of html input with widget `file`
+type File struct {
+	// Name is filename on client side
+	Name string
+
+	// ContentType is MIME type of file declared by client
+	ContentType string
+
+	// Data is content of file
+	Data []byte
+}
+
+// Reader return reader of file content
+func (f File) Reader() io.Reader {
+	return bytes.NewReader(f.Data)
+}
+
+// importPath is import path of package gencf
+const importPath = "github.com/Konstantin8105/gencf"
+
+// fileRuntime is Go source of functions for decode uploaded files
+const fileRuntime = `
+// gencfUpload is uploaded file, see type gencf.File
+type gencfUpload struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// gencfFile return uploaded file of html input. MIME type of file must be
+// in list accept, if list is not empty. Content of file is read up to
+// max+1 bytes, so larger file is rejected by validation. For negative max
+// content is read up to gencfMaxMemory bytes.
+func gencfFile(files map[string][]*multipart.FileHeader, name string, accept []string, max int64, errs FormErrors) (file gencfUpload) {
+	if len(files[name]) == 0 {
+		return
+	}
+	header := files[name][0]
+	contentType := header.Header.Get("Content-Type")
+	if !gencfAcceptType(accept, path.Ext(header.Filename), contentType) {
+		gencfError(errs, name, "file type is not allowed")
+		return
+	}
+	f, err := header.Open()
+	if err != nil {
+		gencfError(errs, name, "cannot open file")
+		return
+	}
+	defer f.Close()
+	limit := max
+	if limit < 0 {
+		limit = gencfMaxMemory
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		gencfError(errs, name, "cannot read file")
+		return
+	}
+	if max < 0 && limit < int64(len(data)) {
+		gencfError(errs, name, "file is too large")
+		return
+	}
+	if !gencfAcceptContent(accept, data) {
+		gencfError(errs, name, "file type is not allowed")
+		return
+	}
+	return gencfUpload{
+		Name:        header.Filename,
+		ContentType: contentType,
+		Data:        data,
+	}
+}
+
+// gencfAcceptType return true if file with extension ext and MIME type
+// contentType match list accept. Elements of list are MIME types
+// ("image/png"), MIME types with any subtype ("image/*") or filename
+// extensions (".pdf").
+func gencfAcceptType(accept []string, ext, contentType string) bool {
+	if len(accept) == 0 {
+		return true
+	}
+	contentType = gencfMediaType(contentType)
+	for _, a := range accept {
+		a = strings.ToLower(a)
+		switch {
+		case strings.HasPrefix(a, "."):
+			if strings.ToLower(ext) == a {
+				return true
+			}
+			if contentType != "" && gencfMediaType(mime.TypeByExtension(a)) == contentType {
+				return true
+			}
+		case strings.HasSuffix(a, "/*"):
+			if strings.HasPrefix(contentType, a[:len(a)-1]) {
+				return true
+			}
+		case a == contentType:
+			return true
+		}
+	}
+	return false
+}
+
+// gencfAcceptContent return true if MIME type of content detected by
+// http.DetectContentType match list accept. Generic types of text and
+// binary content are accepted, because they do not identify content.
+func gencfAcceptContent(accept []string, data []byte) bool {
+	if len(accept) == 0 {
+		return true
+	}
+	contentType := gencfMediaType(http.DetectContentType(data))
+	switch contentType {
+	case "text/plain", "text/xml", "application/octet-stream":
+		return true
+	}
+	return gencfAcceptType(accept, "", contentType)
+}
+
+// gencfMediaType return MIME type without parameters in lower case
+func gencfMediaType(contentType string) string {
+	if index := strings.Index(contentType, ";"); index >= 0 {
+		contentType = contentType[:index]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+`
@@ -1,26 +1,72 @@
-package main
+package gencf
 
 import "fmt"
 
-func createForm(structName string) (err error) {
-	AddImport("fmt")
-	Parameter.Source.WriteString(fmt.Sprintf(
-		`
-func (value %s) FormDefault(handlerName string) (out string){
-	out += "<!DOCTYPE html>\n"
-	out += "<html>\n"
-	out += "<body>\n"`, structName))
-	Parameter.Source.WriteString(`
-	out += fmt.Sprintf("<form action=\"%s\" target=\"_blank\" method=\"GET\">\n", handlerName)
-	out += value.ToHtml()
-	out += "<input type=\"submit\" value=\"Submit\">"
-	out += "</form>"
-	out += "<br>\n"
-	out += "</body>\n"
-	out += "</html>\n"
+func (g *generator) createForm(form *Form) (err error) {
+	// documentation of struct is title of form
+	label, help := splitDoc(form.Doc)
+	g.message(form.Name, label)
+	if help != "" {
+		g.message(helpKey(form.Name), help)
+	}
 
-	return
+	// form with uploaded files
+	var enctype string
+	if g.hasFile(form, map[string]bool{}) {
+		enctype = `
+	if opts.Enctype == "" {
+		opts.Enctype = "multipart/form-data"
+	}`
+	}
+
+	g.addImport("bytes")
+	g.source.WriteString(fmt.Sprintf(`
+func (value %[1]s) WriteForm(w io.Writer, opts FormOptions) error {%[5]s
+	opts = gencfFormOptions(opts)
+	return gencfRender(w, %[2]q, func(s *gencfSlots) interface{} {
+		return gencfForm{
+			FormOptions: opts,
+			Label:       gencfText(opts.Locale, %[1]q, %[3]q),
+			Help:        gencfText(opts.Locale, %[7]q, %[6]q),
+			HTML: s.slot(func(w io.Writer) error {
+				return value.writeHtml(w, %[4]q, opts)
+			}),
+			JS: gencfScript,
+		}
+	})
+}
+
+func (value %[1]s) Form(opts FormOptions) (string, error) {
+	var buf bytes.Buffer
+	if err := value.WriteForm(&buf, opts); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
-`)
+`, form.Name, widgetForm, label, form.Name+".", enctype, help, helpKey(form.Name)))
 	return nil
 }
+
+// hasFile return true if form or nested forms have uploaded files
+func (g *generator) hasFile(form *Form, visited map[string]bool) bool {
+	visited[form.Name] = true
+	var fields func(fs []*Field) bool
+	fields = func(fs []*Field) bool {
+		for _, f := range fs {
+			switch f.Kind {
+			case KindFile:
+				return true
+			case KindGroup:
+				if fields(f.Fields) {
+					return true
+				}
+			case KindStruct, KindSliceStruct:
+				if nested, ok := g.forms[f.Type]; ok && !visited[f.Type] && g.hasFile(nested, visited) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return fields(form.Fields)
+}
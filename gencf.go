@@ -0,0 +1,385 @@
+// Package gencf generate html form from golang struct.
+//
+// Result of generation:
+//
+//	// P is very big struct
+//	type P struct {
+//		A int // A is some value
+//	}
+//
+//	func (p P) ToHtml() (out string){
+//		out += fmt.Printf("P is very big struct\n")
+//		out += fmt.Printf(
+//		"\n%s :<br>\n<input type=\"text\" name=\"%s\" value=\"%s\"><br>\n",
+//		"A is some value","P.A", fmt.Sprintf("%v",p.A))
+//		return
+//	}
+//
+// Function Generate have not global state and is safe for concurrent use.
+package gencf
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Konstantin8105/errors"
+)
+
+// Config is configuration of generation
+type Config struct {
+	// InputFilename is list of Go files with structs
+	InputFilename []string
+
+	// Structs is list of struct names for generation
+	Structs []string
+
+	// PackageName is name of package in generated file
+	PackageName string
+
+	// Log is output for warnings. Warnings are ignored for nil.
+	Log io.Writer
+
+	// Mode of generation. By default: ModeCode.
+	Mode Mode
+
+	// Theme is name of set of widget templates for CSS framework,
+	// see Themes. By default: ThemePlain.
+	Theme string
+
+	// TemplatesDir is folder with html templates of widgets `<widget>.tmpl`,
+	// which replace templates of theme or add new widgets.
+	TemplatesDir string
+
+	// Schema is true for generate JSON Schema (draft 2020-12) file
+	// `<Struct>.schema.json` for each struct
+	Schema bool
+
+	// OpenAPI is format of OpenAPI 3.1 document `openapi.<format>` with
+	// components of structs. Document is not generated for empty format.
+	OpenAPI Format
+
+	// TypeScript is name of TypeScript file with interfaces of structs and
+	// functions for convert them to form data. Name is relative to folder
+	// of generated Go source as names of all generated files, see
+	// OutputFile. File is not generated for empty name.
+	TypeScript string
+
+	// Runtime is name of Go file with types and functions of generated
+	// forms, which is shared by several generations into one package with
+	// same mode and theme. Name is relative to folder of Go source.
+	// By default: RuntimeFilename.
+	Runtime string
+
+	// EmbedRuntime is true for locate runtime in Go source instead of
+	// separate file. Such runtime have only parts used by its forms, so
+	// Go source must be only one generated source in package.
+	EmbedRuntime bool
+}
+
+// RuntimeFilename is default name of Go file with runtime
+const RuntimeFilename = "gencf_gen.go"
+
+// Mode is mode of generation
+type Mode string
+
+const (
+	// ModeCode generate Go code with html as string concatenation
+	ModeCode Mode = "code"
+
+	// ModeTemplate generate html/template file for each struct and Go code
+	// for execute them. Templates are embedded into Go code and must be
+	// located in folder of Go source.
+	ModeTemplate Mode = "template"
+)
+
+// OutputFile is generated file
+type OutputFile struct {
+	// Name is filename relative to folder of generated Go source.
+	// Name of Go source is empty.
+	Name string
+
+	Data []byte
+}
+
+// Generate return Go source with html forms of structs. Runtime is always
+// located in Go source, see Config.EmbedRuntime.
+// Templates of ModeTemplate are not returned, see GenerateFiles.
+func Generate(cfg Config) ([]byte, error) {
+	cfg.EmbedRuntime = true
+	files, err := GenerateFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return files[0].Data, nil
+}
+
+// GenerateFiles return generated files. First file is Go source.
+func GenerateFiles(cfg Config) (files []OutputFile, err error) {
+	_, files, err = generate(cfg)
+	return
+}
+
+// generate return state of generation and generated files
+func generate(cfg Config) (g *generator, files []OutputFile, err error) {
+	switch cfg.Mode {
+	case "", ModeCode, ModeTemplate:
+	default:
+		return nil, nil, fmt.Errorf("not valid mode of generation: %s", cfg.Mode)
+	}
+	switch cfg.OpenAPI {
+	case "", FormatJSON, FormatYAML:
+	default:
+		return nil, nil, fmt.Errorf("not valid format of OpenAPI document: %s", cfg.OpenAPI)
+	}
+
+	forms, err := Parse(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	widgets, err := loadWidgets(cfg.Theme, cfg.TemplatesDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// parsing to HTML, Go
+	et := errors.New("Parsing go to html, html to go")
+	g = &generator{
+		cfg:      cfg,
+		imports:  map[string]bool{},
+		widgets:  widgets,
+		forms:    map[string]*Form{},
+		messages: map[string]string{},
+	}
+	for _, form := range forms {
+		g.forms[form.Name] = form
+	}
+	for _, form := range forms {
+		if cfg.Mode == ModeTemplate {
+			err = g.formTemplate(form)
+		} else {
+			err = g.form(form)
+		}
+		if err != nil {
+			et.Add(err)
+		}
+	}
+	if cfg.Schema {
+		for _, form := range forms {
+			if err = g.schema(form); err != nil {
+				et.Add(err)
+			}
+		}
+	}
+	if cfg.OpenAPI != "" {
+		if err = g.openapi(forms); err != nil {
+			et.Add(err)
+		}
+	}
+	if cfg.TypeScript != "" {
+		g.typescript(forms)
+	}
+	if et.IsError() {
+		return nil, nil, et
+	}
+
+	if cfg.Mode == ModeTemplate {
+		if err = g.widgetsTemplate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// generated source
+	var b []byte
+	if len(forms) > 0 {
+		if cfg.EmbedRuntime {
+			b = g.runtime()
+		} else {
+			name := cfg.Runtime
+			if name == "" {
+				name = RuntimeFilename
+			}
+			g.outputs = append(g.outputs, OutputFile{Name: name, Data: g.runtimeFile()})
+		}
+	}
+	b = append(g.header(), b...)
+	b = append(b, g.source.Bytes()...)
+
+	files = append(files, OutputFile{Data: gofmt(b)})
+	files = append(files, g.templates...)
+	files = append(files, g.outputs...)
+	return g, files, nil
+}
+
+// generator is state of one generation
+type generator struct {
+	cfg Config
+
+	// result source
+	source bytes.Buffer
+
+	// imports of result source
+	imports map[string]bool
+
+	// html templates of ModeTemplate
+	templates []OutputFile
+
+	// other generated files, for example: JSON Schema, OpenAPI, TypeScript
+	outputs []OutputFile
+
+	// html templates of widgets by names
+	widgets map[string]string
+
+	// forms by names of structs
+	forms map[string]*Form
+
+	// messages of source catalog by keys
+	messages map[string]string
+}
+
+func (g *generator) addImport(imp string) {
+	g.imports[imp] = true
+}
+
+// warnf print warning into log
+func (g *generator) warnf(format string, a ...interface{}) {
+	if g.cfg.Log == nil {
+		return
+	}
+	fmt.Fprintf(g.cfg.Log, format, a...)
+}
+
+func (g *generator) form(form *Form) (err error) {
+	// WriteHtml : header
+	g.source.WriteString(fmt.Sprintf(
+		"\nfunc (value %s) writeHtml(w io.Writer, prefix string, opts FormOptions) (err error) {\n", form.Name))
+	g.fieldsToHtml(&g.source, form.Fields)
+	// WriteHtml : footer
+	g.source.WriteString("\treturn\n")
+	g.source.WriteString("}\n\n")
+
+	return g.formMethods(form)
+}
+
+// formMethods write Go source of methods based on method writeHtml
+func (g *generator) formMethods(form *Form) (err error) {
+	// parsing by parts
+	et := errors.New("Parsing errors:")
+
+	// ToHtml
+	g.htmlMethods(form)
+
+	// ToStruct
+	g.decode(form)
+	if err = g.validate(form); err != nil {
+		et.Add(err)
+	}
+	g.fromForm(form)
+
+	// ToForm
+	if err = g.createForm(form); err != nil {
+		et.Add(err)
+	}
+
+	// Handler
+	g.handler(form)
+	if form.Layout == LayoutWizard {
+		if err = g.wizard(form); err != nil {
+			et.Add(err)
+		}
+	}
+
+	if et.IsError() {
+		return et
+	}
+	return nil
+}
+
+// htmlMethods write Go source of html methods based on method writeHtml
+func (g *generator) htmlMethods(form *Form) {
+	g.addImport("io")
+	g.source.WriteString(fmt.Sprintf(`
+func (value %[1]s) toHtml(prefix string, opts FormOptions) (out string) {
+	return string(gencfHtml(func(w io.Writer) error {
+		return value.writeHtml(w, prefix, opts)
+	}))
+}
+
+func (value %[1]s) WriteHtml(w io.Writer) error {
+	return value.writeHtml(w, "%[1]s.", FormOptions{})
+}
+
+// ToHtml return html of struct fields. Error of widget template is
+// returned as text of html, use WriteHtml for get error.
+func (value %[1]s) ToHtml() (out string) {
+	return value.toHtml("%[1]s.", FormOptions{})
+}
+`, form.Name))
+}
+
+// runtimeFile return Go source of file with runtime
+func (g *generator) runtimeFile() []byte {
+	r := &generator{
+		cfg:       g.cfg,
+		imports:   map[string]bool{},
+		templates: g.templates,
+		widgets:   g.widgets,
+	}
+	b := r.runtime()
+	return gofmt(append(r.header(), b...))
+}
+
+// gofmt return Go source simplified by `gofmt`
+func gofmt(b []byte) []byte {
+	// error ignored, because it is not change the workflow
+	if src, err := format.Source(b); err == nil {
+		return src
+	}
+	return b
+}
+
+func (g *generator) header() (b []byte) {
+	var buf bytes.Buffer
+
+	// general comment
+	buf.WriteString("// Code generated by gensf. DO NOT EDIT.\n\n")
+
+	// header
+	buf.WriteString(fmt.Sprintf("package %s\n\n", g.cfg.PackageName))
+
+	// add imports
+	var std, other []string
+	for k := range g.imports {
+		if strings.Contains(strings.Split(k, "/")[0], ".") {
+			other = append(other, k)
+		} else {
+			std = append(std, k)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	switch {
+	case len(std)+len(other) == 0:
+	case len(std)+len(other) == 1:
+		buf.WriteString(fmt.Sprintf("import \"%s\"\n", append(std, other...)[0]))
+	default:
+		buf.WriteString("import (\n")
+		for _, imp := range std {
+			buf.WriteString(fmt.Sprintf("\t\"%s\"\n", imp))
+		}
+		if len(std) > 0 && len(other) > 0 {
+			buf.WriteString("\n")
+		}
+		for _, imp := range other {
+			buf.WriteString(fmt.Sprintf("\t\"%s\"\n", imp))
+		}
+		buf.WriteString(")\n")
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,377 @@
+package gencf
+
+import "fmt"
+
+// handlerRuntime is Go source of types and functions of http handler
+const handlerRuntime = `
+// gencfMaxMemory is maximal size of multipart form in memory, other
+// parts of form are stored on disk
+const gencfMaxMemory = 32 << 20
+
+// HandlerOption is option of form handler
+type HandlerOption func(h *gencfHandler)
+
+// WithFormOptions return handler option with options of html form
+func WithFormOptions(opts FormOptions) HandlerOption {
+	return func(h *gencfHandler) {
+		h.form = opts
+	}
+}
+
+// WithRedirect return handler option with url for redirect after
+// successful submit. By default it is url of request.
+func WithRedirect(url string) HandlerOption {
+	return func(h *gencfHandler) {
+		h.redirect = url
+	}
+}
+
+// WithMaxSize return handler option with maximal size of request body
+// in bytes
+func WithMaxSize(size int64) HandlerOption {
+	return func(h *gencfHandler) {
+		h.maxSize = size
+	}
+}
+
+// WithCSRF return handler option with protection against cross-site
+// request forgery
+func WithCSRF(csrf CSRF) HandlerOption {
+	return func(h *gencfHandler) {
+		h.csrf = csrf
+	}
+}
+
+// gencfFormValue is pointer to value of form
+type gencfFormValue interface {
+	fromForm(form url.Values, files map[string][]*multipart.FileHeader, validate bool) error
+	WriteForm(w io.Writer, opts FormOptions) error
+}
+
+// gencfHandler is http handler of form
+type gencfHandler struct {
+	value    func() gencfFormValue
+	submit   func(ctx context.Context, value gencfFormValue) error
+	form     FormOptions
+	redirect string
+	maxSize  int64
+	csrf     CSRF
+}
+
+func gencfNewHandler(
+	value func() gencfFormValue,
+	submit func(ctx context.Context, value gencfFormValue) error,
+	opts []HandlerOption,
+) *gencfHandler {
+	h := &gencfHandler{value: value, submit: submit}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP show form for GET request and submit form for POST request
+func (h *gencfHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.render(w, r, h.value(), nil, http.StatusOK)
+
+	case http.MethodPost:
+		files, ok := h.parse(w, r)
+		if !ok {
+			return
+		}
+		if r.MultipartForm != nil {
+			defer r.MultipartForm.RemoveAll()
+		}
+		value := h.value()
+		if action := r.PostForm.Get(gencfActionName); action != "" {
+			// change of slice by submit button without script
+			if err := gencfAction(r.PostForm, files, action); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			errs, _ := value.fromForm(r.PostForm, files, false).(FormErrors)
+			h.render(w, r, value, errs, http.StatusOK)
+			return
+		}
+		err := value.fromForm(r.PostForm, files, true)
+		if err == nil {
+			err = h.submit(r.Context(), value)
+		}
+		if err != nil {
+			errs, ok := err.(FormErrors)
+			if !ok {
+				errs = FormErrors{"": err.Error()}
+			}
+			h.render(w, r, value, errs, http.StatusUnprocessableEntity)
+			return
+		}
+		h.done(w, r)
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// parse parse form values of POST request and verify CSRF token.
+// Uploaded files are returned for multipart form. For not valid request
+// error is written and false is returned.
+func (h *gencfHandler) parse(w http.ResponseWriter, r *http.Request) (
+	files map[string][]*multipart.FileHeader, ok bool) {
+	if h.maxSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxSize)
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		if h.csrf != nil {
+			if err := h.csrf.Verify(r); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return nil, false
+			}
+		}
+		return nil, true
+	}
+	if h.csrf != nil {
+		// token is verified by values before first file, so files of
+		// forged request are not stored on disk
+		form, err := gencfMultipartValues(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		r.Form, r.PostForm = form, form
+		err = h.csrf.Verify(r)
+		r.Form, r.PostForm = nil, nil
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return nil, false
+		}
+	}
+	if err := r.ParseMultipartForm(gencfMaxMemory); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return r.MultipartForm.File, true
+}
+
+// gencfMultipartValues return values of multipart form located before
+// first file. Hidden inputs are located before fields of form. Read part
+// of request body is restored.
+func gencfMultipartValues(r *http.Request) (form url.Values, err error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	body := r.Body
+	var read bytes.Buffer
+	defer func() {
+		r.Body = ioutil.NopCloser(io.MultiReader(&read, body))
+	}()
+	reader := multipart.NewReader(io.TeeReader(body, &read), params["boundary"])
+	form = url.Values{}
+	var size int64
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FileName() != "" {
+			return form, nil
+		}
+		value, err := ioutil.ReadAll(io.LimitReader(part, gencfMaxMemory-size+1))
+		if err != nil {
+			return nil, err
+		}
+		if size += int64(len(value)); gencfMaxMemory < size {
+			return nil, fmt.Errorf("values of multipart form are too large")
+		}
+		form.Add(part.FormName(), string(value))
+	}
+}
+
+// done redirect after successful submit (Post/Redirect/Get)
+func (h *gencfHandler) done(w http.ResponseWriter, r *http.Request) {
+	redirect := h.redirect
+	if redirect == "" {
+		redirect = r.URL.RequestURI()
+	}
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// gencfActionName is name of submit buttons with actions for slices
+const gencfActionName = "_action"
+
+// gencfAction change elements of slice in form values and uploaded files
+// by action of submit button:
+//
+//	add:<slice>       add element at the end of slice
+//	remove:<element>  remove element
+//	up:<element>      move element up
+//	down:<element>    move element down
+//
+// where element is "<slice>[<index>]", for example: "remove:M.h[2]".
+// Decoder ignore gaps of indexes, so elements are not renumbered.
+func gencfAction(form url.Values, files map[string][]*multipart.FileHeader, action string) error {
+	colon := strings.Index(action, ":")
+	if colon < 0 {
+		return fmt.Errorf("not valid action: %s", action)
+	}
+	op, name := action[:colon], action[colon+1:]
+	if op == "add" {
+		next := 0
+		if indexes := gencfIndexes(form, name); len(indexes) > 0 {
+			next = indexes[len(indexes)-1] + 1
+		}
+		form.Set(fmt.Sprintf("%s[%d]", name, next), "")
+		return nil
+	}
+
+	open := strings.LastIndex(name, "[")
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return fmt.Errorf("not valid element of slice: %s", name)
+	}
+	slice := name[:open]
+	index, err := strconv.Atoi(name[open+1 : len(name)-1])
+	if err != nil {
+		return fmt.Errorf("not valid element of slice: %s", name)
+	}
+	indexes := gencfIndexes(form, slice)
+	pos := sort.SearchInts(indexes, index)
+	if pos == len(indexes) || indexes[pos] != index {
+		// element is not exist
+		return nil
+	}
+	element := func(pos int) string {
+		return fmt.Sprintf("%s[%d]", slice, indexes[pos])
+	}
+	switch op {
+	case "remove":
+		gencfRename(form, files, name, "")
+	case "up":
+		if pos > 0 {
+			gencfSwap(form, files, name, element(pos-1))
+		}
+	case "down":
+		if pos+1 < len(indexes) {
+			gencfSwap(form, files, name, element(pos+1))
+		}
+	default:
+		return fmt.Errorf("not valid action: %s", action)
+	}
+	return nil
+}
+
+// gencfSwap swap values of slice elements a and b
+func gencfSwap(form url.Values, files map[string][]*multipart.FileHeader, a, b string) {
+	const temp = "\x00"
+	gencfRename(form, files, a, temp)
+	gencfRename(form, files, b, a)
+	gencfRename(form, files, temp, b)
+}
+
+// gencfRename replace name of slice element from by to in names of
+// values. For empty to values of element are removed.
+func gencfRename(form url.Values, files map[string][]*multipart.FileHeader, from, to string) {
+	match := func(key string) bool {
+		return key == from || strings.HasPrefix(key, from+".") || strings.HasPrefix(key, from+"[")
+	}
+	var keys []string
+	for key := range form {
+		if match(key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		if to != "" {
+			form[to+key[len(from):]] = form[key]
+		}
+		delete(form, key)
+	}
+	keys = nil
+	for key := range files {
+		if match(key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		if to != "" {
+			files[to+key[len(from):]] = files[key]
+		}
+		delete(files, key)
+	}
+}
+
+// render write html page with form
+func (h *gencfHandler) render(w http.ResponseWriter, r *http.Request,
+	value gencfFormValue, errs FormErrors, status int) {
+	h.write(w, r, errs, nil, status, value.WriteForm)
+}
+
+// write write html page of function page with options of form, errors
+// and additional hidden inputs
+func (h *gencfHandler) write(w http.ResponseWriter, r *http.Request,
+	errs FormErrors, hidden map[string]string, status int,
+	page func(w io.Writer, opts FormOptions) error) {
+	opts := h.form
+	opts.Errors = errs
+	if opts.Locale == "" {
+		opts.Locale = gencfLocale(r)
+	}
+	if h.csrf != nil || len(hidden) > 0 {
+		opts.Hidden = map[string]string{}
+		for k, v := range h.form.Hidden {
+			opts.Hidden[k] = v
+		}
+		for k, v := range hidden {
+			opts.Hidden[k] = v
+		}
+	}
+	if h.csrf != nil {
+		name, token, err := h.csrf.Token(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		opts.Hidden[name] = token
+	}
+	var buf bytes.Buffer
+	if err := page(&buf, opts); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+`
+
+// handlerName return name of function for create http handler of struct
+func handlerName(name string) string {
+	return "New" + name + "Handler"
+}
+
+// handler write Go source of function for create http handler of struct
+func (g *generator) handler(form *Form) {
+	g.addImport("context")
+	g.addImport("net/http")
+	g.source.WriteString(fmt.Sprintf(`
+func %[2]s(onSubmit func(ctx context.Context, value *%[1]s) error, opts ...HandlerOption) http.Handler {
+	return gencfNewHandler(
+		func() gencfFormValue {
+			return new(%[1]s)
+		},
+		func(ctx context.Context, value gencfFormValue) error {
+			return onSubmit(ctx, value.(*%[1]s))
+		},
+		opts)
+}
+`, form.Name, handlerName(form.Name)))
+}
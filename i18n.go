@@ -0,0 +1,139 @@
+package gencf
+
+// i18nRuntime is Go source of message catalogs
+const i18nRuntime = `
+// gencfCatalogs is messages by keys for locales
+var gencfCatalogs = struct {
+	sync.RWMutex
+	m map[string]map[string]string
+}{m: map[string]map[string]string{}}
+
+// AddCatalog add messages of locale, for example "de" or "de-AT".
+// Keys of messages are:
+//
+//	"<Struct>"              title of form
+//	"<Struct>#help"         description of form
+//	"<Struct>.<Field>"      label of field
+//	"<Struct>.<Field>#help" help of field
+//	"<message>"             message, for example "value is required"
+//
+// Source catalog is printed by gencf with flag -extract-messages.
+func AddCatalog(locale string, messages map[string]string) {
+	gencfCatalogs.Lock()
+	defer gencfCatalogs.Unlock()
+	catalog, ok := gencfCatalogs.m[locale]
+	if !ok {
+		catalog = map[string]string{}
+		gencfCatalogs.m[locale] = catalog
+	}
+	for key, message := range messages {
+		catalog[key] = message
+	}
+}
+
+// LoadCatalog add messages of locale from JSON object with messages
+// by keys, see AddCatalog
+func LoadCatalog(locale string, r io.Reader) error {
+	var messages map[string]string
+	if err := json.NewDecoder(r).Decode(&messages); err != nil {
+		return fmt.Errorf("cannot load catalog of locale %s: %v", locale, err)
+	}
+	AddCatalog(locale, messages)
+	return nil
+}
+
+// gencfText return message of key for locale. For locale "de-AT"
+// messages of locales "de-AT" and "de" are used. For not exist message
+// it is fallback.
+func gencfText(locale, key, fallback string) string {
+	if locale == "" {
+		return fallback
+	}
+	gencfCatalogs.RLock()
+	defer gencfCatalogs.RUnlock()
+	for {
+		if message := gencfCatalogs.m[locale][key]; message != "" {
+			return message
+		}
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			return fallback
+		}
+		locale = locale[:i]
+	}
+}
+
+// Localize return errors with messages for locale
+func (errs FormErrors) Localize(locale string) FormErrors {
+	if locale == "" || errs == nil {
+		return errs
+	}
+	localized := FormErrors{}
+	for name, message := range errs {
+		localized[name] = gencfText(locale, message, message)
+	}
+	return localized
+}
+
+// gencfLocale return first language of request header Accept-Language
+func gencfLocale(r *http.Request) string {
+	locale := r.Header.Get("Accept-Language")
+	if i := strings.IndexAny(locale, ",;"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.TrimSpace(locale)
+	if locale == "*" {
+		return ""
+	}
+	return locale
+}
+`
+
+// runtimeMessages is messages of generated runtime, which are not
+// depend on structs
+var runtimeMessages = []string{
+	"Submit",
+	"not valid boolean value",
+	"not valid integer value",
+	"not valid unsigned integer value",
+	"not valid number",
+	"not valid complex number",
+	"Data",
+	"Add",
+	"Remove",
+	"Move up",
+	"Move down",
+	"Move",
+}
+
+// fileMessages is messages of runtime of uploaded files
+var fileMessages = []string{
+	"file type is not allowed",
+	"cannot open file",
+	"cannot read file",
+	"file is too large",
+}
+
+// helpKey return key of message with help for key of label
+func helpKey(key string) string {
+	return key + "#help"
+}
+
+// message add message of source catalog
+func (g *generator) message(key, message string) {
+	g.messages[key] = message
+}
+
+// ExtractMessages return source catalog of messages by keys: labels and
+// help of structs and fields, messages of validation.
+// See AddCatalog in generated source.
+func ExtractMessages(cfg Config) (messages map[string]string, err error) {
+	g, _, err := generate(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range runtimeMessages {
+		g.message(message, message)
+	}
+	return g.messages, nil
+}
@@ -0,0 +1,640 @@
+package gencf
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Konstantin8105/errors"
+)
+
+// Kind is kind of form field
+type Kind string
+
+const (
+	// KindBasic is field with Go basic type, for example: int, string
+	KindBasic Kind = "basic"
+
+	// KindStruct is field with user struct type
+	KindStruct Kind = "struct"
+
+	// KindGroup is field with anonymous struct. Fields of group are
+	// located in Field.Fields.
+	KindGroup Kind = "group"
+
+	// KindSliceBasic is slice or array of Go basic type
+	KindSliceBasic Kind = "slice-basic"
+
+	// KindSliceStruct is slice or array of user struct type
+	KindSliceStruct Kind = "slice-struct"
+
+	// KindFile is uploaded file: field with type gencf.File or []byte
+	// with widget `file`
+	KindFile Kind = "file"
+
+	// KindUnsupported is field with not supported type
+	KindUnsupported Kind = "unsupported"
+)
+
+// Form is intermediate representation of Go struct between parsing of
+// Go source and generation of outputs
+type Form struct {
+	// Name of struct
+	Name string `json:"name"`
+
+	// Doc is documentation of struct
+	Doc string `json:"doc,omitempty"`
+
+	// Layout of groups of fields, see option `group` of tag `form`.
+	// By default: LayoutFieldset.
+	Layout Layout `json:"layout,omitempty"`
+
+	// Columns is amount of columns of LayoutColumns
+	Columns int `json:"columns,omitempty"`
+
+	// Fields of struct
+	Fields []*Field `json:"fields"`
+}
+
+// Layout is layout of groups of fields
+type Layout string
+
+const (
+	// LayoutFieldset is each group in own fieldset
+	LayoutFieldset Layout = "fieldset"
+
+	// LayoutTabs is groups in tabs
+	LayoutTabs Layout = "tabs"
+
+	// LayoutColumns is groups in columns
+	LayoutColumns Layout = "columns"
+
+	// LayoutWizard is multi-step form: each group and each field without
+	// group is step. Groups are fieldsets.
+	LayoutWizard Layout = "wizard"
+)
+
+// defaultColumns is amount of columns of LayoutColumns by default
+const defaultColumns = 2
+
+// directivePrefix is prefix of struct directives in documentation of
+// struct, for example: `//gencf:layout=tabs`
+const directivePrefix = "//gencf:"
+
+// Field is intermediate representation of struct field
+type Field struct {
+	Kind Kind `json:"kind"`
+
+	// Struct is name of struct with field
+	Struct string `json:"struct"`
+
+	// Name is Go name of field
+	Name string `json:"name"`
+
+	// Path is name path of field from struct.
+	// For example: "d.e" for field `e` in anonymous struct field `d`.
+	Path string `json:"path"`
+
+	// Label is first sentence of documentation of field
+	Label string `json:"label,omitempty"`
+
+	// Help is description of field: documentation after first sentence
+	// and trailing comment of field
+	Help string `json:"help,omitempty"`
+
+	// Type is Go type of field or type of slice element.
+	// For not supported types it is name of AST type.
+	Type string `json:"type"`
+
+	// Array is true for array of Go basic type or user type(struct)
+	Array bool `json:"array,omitempty"`
+
+	// Widget is name of widget template. For slice of Go basic type it is
+	// widget of slice element.
+	Widget string `json:"widget"`
+
+	Constraints Constraints `json:"constraints"`
+
+	// Options of widget `select`
+	Options []string `json:"options,omitempty"`
+
+	// Accept is allowable MIME types or filename extensions of widget `file`
+	Accept []string `json:"accept,omitempty"`
+
+	// Group is name of group of fields in html form. Input names are not
+	// depend on group.
+	Group string `json:"group,omitempty"`
+
+	// Order of field in html form. Fields are sorted by order, fields with
+	// same order are in order of declaration.
+	Order int `json:"order,omitempty"`
+
+	// ShowIf is condition of field visibility. Hidden field is not
+	// validated.
+	ShowIf *Condition `json:"showif,omitempty"`
+
+	// Fields of group
+	Fields []*Field `json:"fields,omitempty"`
+}
+
+// Condition of field visibility
+type Condition struct {
+	// Field is name path of field with Go basic type in same struct
+	Field string `json:"field"`
+
+	// Values of field, which show field. For empty values field is shown
+	// for not zero value of field.
+	Values []string `json:"values,omitempty"`
+}
+
+// Constraints of field value
+type Constraints struct {
+	Required bool   `json:"required,omitempty"`
+	Min      string `json:"min,omitempty"`
+	Max      string `json:"max,omitempty"`
+	Pattern  string `json:"pattern,omitempty"`
+}
+
+// widgets of fields by default
+const (
+	defaultWidget            = "text"
+	defaultWidgetNumber      = "number"
+	defaultWidgetCheckbox    = "checkbox"
+	defaultWidgetFieldset    = "fieldset"
+	defaultWidgetSlice       = "slice"
+	defaultWidgetSliceStruct = "slice-struct"
+	defaultWidgetFile        = "file"
+	defaultWidgetShowIf      = "showif"
+)
+
+// Parse return intermediate representation of structs
+func Parse(cfg Config) (forms []*Form, err error) {
+	// check input data
+	et := errors.New("Check input data")
+	if len(cfg.InputFilename) == 0 {
+		et.Add(fmt.Errorf("input file/files is not added"))
+	}
+	if len(cfg.Structs) == 0 {
+		et.Add(fmt.Errorf("name of struct is not added"))
+	}
+	if cfg.PackageName == "" {
+		et.Add(fmt.Errorf("name of package is empty"))
+	}
+	for i := range cfg.InputFilename {
+		_, err := os.Stat(cfg.InputFilename[i])
+		if err != nil {
+			et.Add(fmt.Errorf("input file `%s` is not exist", cfg.InputFilename[i]))
+		}
+	}
+	if et.IsError() {
+		return nil, et
+	}
+
+	// parsing file to ast
+	var files []*ast.File
+	et.Name = "parsing Go files to AST"
+	for _, filename := range cfg.InputFilename {
+		f, err := parser.ParseFile(
+			token.NewFileSet(),
+			filename,
+			nil,
+			parser.ParseComments)
+		if err != nil {
+			et.Add(fmt.Errorf("Cannot parse file : %s", filename)).
+				Add(err)
+		} else {
+			files = append(files, f)
+		}
+	}
+	if et.IsError() {
+		return nil, et
+	}
+
+	// parsing to intermediate representation
+	et.Name = "Parsing go structs"
+	for i := range files {
+		for k := range files[i].Decls {
+			decl, ok := files[i].Decls[k].(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for j := range cfg.Structs {
+				form, err := parseForm(decl, cfg.Structs[j], cfg.Log)
+				if err != nil {
+					et.Add(err)
+					continue
+				}
+				if form != nil {
+					forms = append(forms, form)
+				}
+			}
+		}
+	}
+	if et.IsError() {
+		return nil, et
+	}
+
+	return forms, nil
+}
+
+// parseForm return nil form, if declaration is not struct with name
+// `structName`
+func parseForm(decl *ast.GenDecl, structName string, log io.Writer) (form *Form, err error) {
+	// check : is this ast have struct name
+	if decl.Tok != token.TYPE {
+		return
+	}
+	if len(decl.Specs) != 1 {
+		return
+	}
+	if _, ok := decl.Specs[0].(*ast.TypeSpec); !ok {
+		return
+	}
+	tc := decl.Specs[0].(*ast.TypeSpec)
+	if tc.Name.Name != structName {
+		return
+	}
+
+	// is this struct
+	fl, ok := tc.Type.(*ast.StructType)
+	if !ok {
+		err = fmt.Errorf("Not StructType type : %T", tc.Type)
+		return
+	}
+
+	form = &Form{
+		Name: structName,
+		Doc:  docs(decl.Doc),
+	}
+	if err = form.parseDirectives(decl.Doc); err != nil {
+		return nil, fmt.Errorf("Struct %s: %v", structName, err)
+	}
+	form.Fields, err = parseFields(fl, structName, "", log)
+	if err != nil {
+		return
+	}
+	err = form.checkConditions(form.Fields)
+	return
+}
+
+// checkConditions return error for conditions of fields with not exist
+// fields
+func (form *Form) checkConditions(fields []*Field) error {
+	et := errors.New(fmt.Sprintf("Conditions of struct %s", form.Name))
+	for _, f := range fields {
+		if f.ShowIf != nil {
+			c := form.field(f.ShowIf.Field)
+			switch {
+			case c == nil:
+				et.Add(fmt.Errorf("Field %s: field `%s` of condition is not found", f.Path, f.ShowIf.Field))
+			case c.Kind != KindBasic:
+				et.Add(fmt.Errorf("Field %s: field `%s` of condition have not Go basic type", f.Path, f.ShowIf.Field))
+			case c == f:
+				et.Add(fmt.Errorf("Field %s: condition by same field", f.Path))
+			case f.Kind == KindGroup:
+				et.Add(fmt.Errorf("Field %s: condition of group is not supported", f.Path))
+			default:
+				// conditions of hidden fields are conditions of field.
+				// Not exist field of condition is error of other field.
+				for visited := map[*Field]bool{f: true}; c != nil && c.ShowIf != nil; c = form.field(c.ShowIf.Field) {
+					if visited[c] {
+						et.Add(fmt.Errorf("Field %s: cycle of conditions", f.Path))
+						break
+					}
+					visited[c] = true
+				}
+			}
+		}
+		if f.Kind == KindGroup {
+			if err := form.checkConditions(f.Fields); err != nil {
+				et.Add(err)
+			}
+		}
+	}
+	if et.IsError() {
+		return et
+	}
+	return nil
+}
+
+// field return field with name path or nil
+func (form *Form) field(path string) *Field {
+	var find func(fields []*Field) *Field
+	find = func(fields []*Field) *Field {
+		for _, f := range fields {
+			if f.Path == path {
+				return f
+			}
+			if f.Kind == KindGroup && strings.HasPrefix(path, f.Path+".") {
+				return find(f.Fields)
+			}
+		}
+		return nil
+	}
+	return find(form.Fields)
+}
+
+// parseDirectives parse struct directives, for example:
+//
+//	//gencf:layout=tabs
+//	//gencf:layout=columns,columns=3
+//	//gencf:layout=wizard
+func (form *Form) parseDirectives(cg *ast.CommentGroup) error {
+	if cg == nil {
+		return nil
+	}
+	for _, c := range cg.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
+		for _, opt := range strings.Split(c.Text[len(directivePrefix):], ",") {
+			opt = strings.TrimSpace(opt)
+			var val string
+			if index := strings.Index(opt, "="); index >= 0 {
+				opt, val = opt[:index], opt[index+1:]
+			}
+			switch opt {
+			case "layout":
+				switch Layout(val) {
+				case LayoutFieldset, LayoutTabs, LayoutColumns, LayoutWizard:
+				default:
+					return fmt.Errorf("not valid layout `%s`", val)
+				}
+				form.Layout = Layout(val)
+			case "columns":
+				n, err := strconv.Atoi(val)
+				if err != nil || n < 1 {
+					return fmt.Errorf("not valid amount of columns `%s`", val)
+				}
+				form.Columns = n
+			default:
+				return fmt.Errorf("not valid directive `%s`", opt)
+			}
+		}
+	}
+	return nil
+}
+
+func parseFields(st *ast.StructType, structName, prefix string, log io.Writer) (fields []*Field, err error) {
+	et := errors.New("Parsing errors:")
+	for _, a := range st.Fields.List {
+		f, err := parseField(a, structName, prefix, log)
+		if err != nil {
+			et.Add(err)
+			continue
+		}
+		fields = append(fields, f)
+	}
+	if et.IsError() {
+		err = et
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Order < fields[j].Order
+	})
+	return
+}
+
+func parseField(a *ast.Field, structName, prefix string, log io.Writer) (f *Field, err error) {
+	if len(a.Names) != 1 {
+		// Panic with debug information for understood
+		err = fmt.Errorf("Too many names\n")
+		return
+	}
+
+	f = &Field{
+		Struct: structName,
+		Name:   a.Names[0].Name,
+		Path:   prefix + a.Names[0].Name,
+	}
+	var help string
+	f.Label, help = splitDoc(docs(a.Doc))
+	comment := docs(a.Comment)
+	if f.Label == "" {
+		// only trailing comment, for example: `A int // A is some value`
+		f.Label, comment = splitDoc(comment)
+	}
+	f.Help = joinDoc(help, comment)
+
+	// not allowable empty documentation
+	if len(f.Label) == 0 && log != nil {
+		// if docs is empty
+		fmt.Fprintf(log, "Struct `%s` haven`t documentation\n", structName+"."+prefix)
+	}
+
+	// convert types
+	switch v := a.Type.(type) {
+	case *ast.StructType:
+		// parse nested struct
+		f.Kind = KindGroup
+		f.Type = "struct"
+		f.Widget = defaultWidgetFieldset
+		f.Fields, err = parseFields(v, structName, f.Path+".", log)
+		if err != nil {
+			return
+		}
+
+	case *ast.Ident:
+		f.Type = v.Name
+		if isBasic(v.Name) {
+			f.Kind = KindBasic
+			f.Widget = basicWidget(v.Name)
+		} else {
+			f.Kind = KindStruct
+			f.Widget = defaultWidgetFieldset
+		}
+
+	case *ast.ArrayType:
+		elt, ok := v.Elt.(*ast.Ident)
+		if !ok {
+			f.Kind = KindUnsupported
+			f.Type = fmt.Sprintf("%T", v)
+			break
+		}
+		f.Type = elt.Name
+		f.Array = v.Len != nil
+		if isBasic(elt.Name) {
+			f.Kind = KindSliceBasic
+			f.Widget = basicWidget(elt.Name)
+		} else {
+			f.Kind = KindSliceStruct
+			f.Widget = defaultWidgetSliceStruct
+		}
+
+	case *ast.SelectorExpr:
+		// type gencf.File
+		if pkg, ok := v.X.(*ast.Ident); ok && pkg.Name == "gencf" && v.Sel.Name == "File" {
+			f.Kind = KindFile
+			f.Type = "gencf.File"
+			f.Widget = defaultWidgetFile
+			break
+		}
+		f.Kind = KindUnsupported
+		f.Type = fmt.Sprintf("%T", v)
+
+	default:
+		f.Kind = KindUnsupported
+		f.Type = fmt.Sprintf("%T", v)
+	}
+
+	if a.Tag != nil {
+		if err = f.parseTag(a.Tag.Value); err != nil {
+			return nil, fmt.Errorf("Field %s: %v", f.Path, err)
+		}
+	}
+
+	// uploaded file as slice of bytes
+	if f.Kind == KindSliceBasic && !f.Array && f.Widget == defaultWidgetFile &&
+		(f.Type == "byte" || f.Type == "uint8") {
+		f.Kind = KindFile
+		f.Type = "[]byte"
+	}
+
+	return
+}
+
+// basicWidget return widget by default for Go basic type
+func basicWidget(name string) string {
+	switch name {
+	case "bool":
+		return defaultWidgetCheckbox
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"byte", "rune",
+		"float32", "float64":
+		return defaultWidgetNumber
+	}
+	return defaultWidget
+}
+
+// parseTag parse struct tag `form`, for example:
+//
+//	`form:"widget=number,required,min=1,max=10"`
+//	`form:"widget=select,options=Simple|Advanced"`
+//	`form:"widget=file,accept=image/*|.pdf,max=1048576"`
+//	`form:"group=Network,order=3"`
+//	`form:"showif=TLS"`
+//	`form:"showif=Mode=Advanced|Expert"`
+func (f *Field) parseTag(tag string) error {
+	tag, err := strconv.Unquote(tag)
+	if err != nil {
+		return err
+	}
+	value, ok := reflect.StructTag(tag).Lookup("form")
+	if !ok {
+		return nil
+	}
+	for _, opt := range strings.Split(value, ",") {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		var val string
+		if index := strings.Index(opt, "="); index >= 0 {
+			opt, val = opt[:index], opt[index+1:]
+		}
+		switch opt {
+		case "widget":
+			f.Widget = val
+		case "required":
+			f.Constraints.Required = true
+		case "min":
+			f.Constraints.Min = val
+		case "max":
+			f.Constraints.Max = val
+		case "pattern":
+			if _, err := regexp.Compile(val); err != nil {
+				return fmt.Errorf("not valid pattern `%s`: %v", val, err)
+			}
+			f.Constraints.Pattern = val
+		case "options":
+			f.Options = strings.Split(val, "|")
+		case "accept":
+			f.Accept = strings.Split(val, "|")
+		case "group":
+			if val == "" {
+				return fmt.Errorf("name of group is empty")
+			}
+			f.Group = val
+		case "order":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return fmt.Errorf("not valid order `%s`", val)
+			}
+			f.Order = n
+		case "showif":
+			c := &Condition{Field: val}
+			if index := strings.Index(val, "="); index >= 0 {
+				c.Field, c.Values = val[:index], strings.Split(val[index+1:], "|")
+			}
+			if c.Field == "" {
+				return fmt.Errorf("field of condition is empty")
+			}
+			f.ShowIf = c
+		default:
+			return fmt.Errorf("not valid option `%s` of tag `form`", opt)
+		}
+	}
+	return nil
+}
+
+// isBasic return true for Go`s basic types
+func isBasic(name string) bool {
+	switch name {
+	case "bool",
+		"string",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"byte", // alias for uint8
+		"rune", // alias for int32 represents a Unicode code point
+		"float32", "float64",
+		"complex64", "complex128":
+		return true
+	}
+	return false
+}
+
+// splitDoc return first sentence of documentation and rest of it
+func splitDoc(doc string) (first, rest string) {
+	if index := strings.Index(doc, ". "); index >= 0 {
+		return doc[:index+1], strings.TrimSpace(doc[index+2:])
+	}
+	return doc, ""
+}
+
+// joinDoc return documentation from parts separated by space
+func joinDoc(parts ...string) string {
+	var ps []string
+	for _, p := range parts {
+		if p != "" {
+			ps = append(ps, p)
+		}
+	}
+	return strings.Join(ps, " ")
+}
+
+// doc return whole documentation of field
+func (f *Field) doc() string {
+	return joinDoc(f.Label, f.Help)
+}
+
+// docs return text of comments
+func docs(cg *ast.CommentGroup) (s string) {
+	if cg == nil {
+		return
+	}
+	for i := 0; i < len(cg.List); i++ {
+		if strings.HasPrefix(cg.List[i].Text, directivePrefix) {
+			continue
+		}
+		s += cg.List[i].Text[2:] // [2:] for remove words:"//","/*"
+	}
+	return strings.TrimSpace(s)
+}
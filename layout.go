@@ -0,0 +1,180 @@
+package gencf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// section is fields of one group
+type section struct {
+	// group is name of group
+	group string
+
+	// fields of group in order of html form. Fields are copies of
+	// fields without group.
+	fields []*Field
+}
+
+// name return name of html template and key of label of group
+func (s *section) name() string {
+	f := s.fields[0]
+	return strings.TrimSuffix(f.Struct+"."+s.parent(), ".") + "@" + s.group
+}
+
+// parent return name path of struct field with fields of group
+func (s *section) parent() string {
+	f := s.fields[0]
+	return strings.TrimSuffix(f.Path, f.Name)
+}
+
+// block is part of html of fields: field without group or groups shown
+// by one widget template
+type block struct {
+	field  *Field
+	groups []*section
+
+	// layout of groups
+	layout Layout
+}
+
+// key return key of block data in data of html template
+func (b block) key() string {
+	if b.layout == LayoutFieldset {
+		return "@" + b.groups[0].group
+	}
+	return "@"
+}
+
+// title return names of groups of block
+func (b block) title() string {
+	var names []string
+	for _, s := range b.groups {
+		names = append(names, s.group)
+	}
+	return strings.Join(names, ", ")
+}
+
+// blocks return blocks of fields in order of html form. Group is located
+// at place of its first field. For layouts with tabs and columns all groups
+// are located at place of first group.
+func (g *generator) blocks(fields []*Field) (bs []block) {
+	if len(fields) == 0 {
+		return nil
+	}
+	layout := g.layout(fields[0])
+	if layout == LayoutWizard {
+		// steps of wizard are groups and fields
+		layout = LayoutFieldset
+	}
+	groups := map[string]*section{}
+	all := -1 // index of block with all groups
+	for _, f := range fields {
+		if f.Group == "" {
+			bs = append(bs, block{field: f})
+			continue
+		}
+		group := f.Group
+		c := *f
+		c.Group = ""
+		f = &c
+		if s, ok := groups[group]; ok {
+			s.fields = append(s.fields, f)
+			continue
+		}
+		s := &section{group: group, fields: []*Field{f}}
+		groups[group] = s
+		switch {
+		case layout == LayoutFieldset:
+			bs = append(bs, block{groups: []*section{s}, layout: layout})
+		case all < 0:
+			all = len(bs)
+			bs = append(bs, block{groups: []*section{s}, layout: layout})
+		default:
+			bs[all].groups = append(bs[all].groups, s)
+		}
+	}
+	return
+}
+
+// layout return layout of groups in struct with field
+func (g *generator) layout(f *Field) Layout {
+	if form, ok := g.forms[f.Struct]; ok && form.Layout != "" {
+		return form.Layout
+	}
+	return LayoutFieldset
+}
+
+// fieldsToHtml write Go code with html of fields into function writeHtml
+func (g *generator) fieldsToHtml(source *bytes.Buffer, fields []*Field) {
+	for _, b := range g.blocks(fields) {
+		g.blockToHtml(source, b)
+	}
+}
+
+// blockToHtml write Go code with html of block of fields
+func (g *generator) blockToHtml(source *bytes.Buffer, b block) {
+	if b.field != nil {
+		g.structToHtml(source, b.field)
+		return
+	}
+	source.WriteString("\n")
+	source.WriteString(fmt.Sprintf("	/"+"/ Group : %s\n", b.title())) // comment
+	source.WriteString(fmt.Sprintf("\tif err = %s; err != nil {\n\t\treturn\n\t}\n",
+		render(g.blockWidget(b), g.blockData(b))))
+}
+
+// blockWidget return name of widget template for groups of block
+func (g *generator) blockWidget(b block) string {
+	return string(b.layout)
+}
+
+// blockData return Go expression with data of widget template for groups
+// of block
+func (g *generator) blockData(b block) string {
+	if b.layout == LayoutFieldset {
+		return g.sectionData(b.groups[0])
+	}
+	columns := g.forms[b.groups[0].fields[0].Struct].Columns
+	if columns == 0 {
+		columns = defaultColumns
+	}
+	var buf bytes.Buffer
+	buf.WriteString("gencfLayout{\n")
+	buf.WriteString(fmt.Sprintf("ID: gencfID(opts.ID, prefix+%q),\n", b.groups[0].parent()+"_groups"))
+	buf.WriteString(fmt.Sprintf("Columns: %d,\n", columns))
+	buf.WriteString("Groups: []gencfField{\n")
+	for _, s := range b.groups {
+		buf.WriteString(strings.TrimPrefix(g.sectionData(s), "gencfField"))
+		buf.WriteString(",\n")
+	}
+	buf.WriteString("},\n")
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// sectionData return Go expression with data of widget template for group
+func (g *generator) sectionData(s *section) string {
+	g.message(s.name(), s.group)
+	var buf bytes.Buffer
+	buf.WriteString("gencfField{\n")
+	buf.WriteString(fmt.Sprintf("ID: gencfID(opts.ID, prefix+%q),\n", s.parent()+"_group."+s.group))
+	buf.WriteString(fmt.Sprintf("Label: gencfText(opts.Locale, %q, %q),\n", s.name(), s.group))
+	buf.WriteString(fmt.Sprintf("Widget: %q,\n", defaultWidgetFieldset))
+	buf.WriteString(fmt.Sprintf("HTML: %s,\n", g.fieldsHtml(s.name(), s.fields)))
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// fieldsHtml return Go expression with slot of html of fields. For
+// ModeTemplate fields are shown by html template with name `name`.
+func (g *generator) fieldsHtml(name string, fields []*Field) string {
+	if g.cfg.Mode == ModeTemplate {
+		return slot("return " + render(name, g.templateData(fields)))
+	}
+
+	var source bytes.Buffer
+	g.fieldsToHtml(&source, fields)
+	source.WriteString("\treturn")
+	return slot(source.String())
+}
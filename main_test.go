@@ -1,13 +1,19 @@
-package main
+package gencf
 
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"html/template"
 	"io/ioutil"
 	"math"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -22,22 +28,23 @@ func TestRun(t *testing.T) {
 			continue
 		}
 		t.Run(tf, func(t *testing.T) {
-			ResetParameter()
-			Parameter.InputFilename = []string{tf}
-			Parameter.OutputFilename = tf[:len(tf)-4] + ".gen.got"
-			Parameter.Structs = []string{"TestStruct", "Se"}
+			outputFilename := tf[:len(tf)-4] + ".gen.got"
 
-			err := run()
+			bAct, err := Generate(Config{
+				InputFilename: []string{tf},
+				Structs:       []string{"TestStruct", "Se"},
+				PackageName:   "main",
+			})
 			if err != nil {
 				t.Fatal(err)
 			}
-
-			// compare results of parsing
-			bAct, err := ioutil.ReadFile(Parameter.OutputFilename)
+			err = ioutil.WriteFile(outputFilename, bAct, 0644)
 			if err != nil {
 				t.Fatal(err)
 			}
-			bExp, err := ioutil.ReadFile(Parameter.OutputFilename + ".expected")
+
+			// compare results of parsing
+			bExp, err := ioutil.ReadFile(outputFilename + ".expected")
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -49,6 +56,450 @@ func TestRun(t *testing.T) {
 	}
 }
 
+// TestGolden compare generated files with expected files
+// `testdata/<N>.<suffix>.gen.got.expected`. All files are in one file
+// separated by names. Go source of ModeCode is checked by TestRun.
+func TestGolden(t *testing.T) {
+	testFiles, err := filepath.Glob(filepath.FromSlash("testdata/" + "*.got"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		cfg    Config
+		suffix string
+	}{
+		{"template", Config{Mode: ModeTemplate}, "tmpl"},
+		{"schema", Config{Schema: true}, "schema"},
+		{"openapi", Config{OpenAPI: FormatYAML}, "openapi"},
+		{"typescript", Config{TypeScript: "forms.ts"}, "ts"},
+	} {
+		for _, tf := range testFiles {
+			if strings.Contains(tf, ".gen.got") {
+				continue
+			}
+			t.Run(tc.name+"/"+tf, func(t *testing.T) {
+				outputFilename := tf[:len(tf)-4] + "." + tc.suffix + ".gen.got"
+
+				cfg := tc.cfg
+				cfg.InputFilename = []string{tf}
+				cfg.Structs = []string{"TestStruct", "Se"}
+				cfg.PackageName = "main"
+				cfg.EmbedRuntime = true
+				files, err := GenerateFiles(cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cfg.Mode != ModeTemplate {
+					files = files[1:]
+				}
+
+				// all files in one
+				var buf bytes.Buffer
+				for _, f := range files {
+					fmt.Fprintf(&buf, "-- %s --\n", f.Name)
+					buf.Write(f.Data)
+				}
+				bAct := buf.Bytes()
+				err = ioutil.WriteFile(outputFilename, bAct, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// compare results of parsing
+				bExp, err := ioutil.ReadFile(outputFilename + ".expected")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Equal(bAct, bExp) {
+					t.Errorf("%s", ShowDiff(string(bAct), string(bExp)))
+				}
+			})
+		}
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	testFiles, err := filepath.Glob(filepath.FromSlash("testdata/" + "*.got"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for _, tf := range testFiles {
+		if strings.Contains(tf, ".gen.got") {
+			continue
+		}
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(tf string) {
+				defer wg.Done()
+				bAct, err := Generate(Config{
+					InputFilename: []string{tf},
+					Structs:       []string{"TestStruct", "Se"},
+					PackageName:   "main",
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				bExp, err := ioutil.ReadFile(tf[:len(tf)-4] + ".gen.got.expected")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !bytes.Equal(bAct, bExp) {
+					t.Errorf("%s: %s", tf, ShowDiff(string(bAct), string(bExp)))
+				}
+			}(tf)
+		}
+	}
+	wg.Wait()
+}
+
+func TestPackageName(t *testing.T) {
+	b, err := Generate(Config{
+		InputFilename: []string{filepath.FromSlash("testdata/2.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte("\npackage test\n")) {
+		t.Errorf("package name is not used:\n%s", b)
+	}
+}
+
+func TestBench(t *testing.T) {
+	// generated files of benchmark are up to date. Two generations
+	// share one runtime file.
+	for output, structs := range map[string][]string{
+		"large_gen.go":   {"Large", "Node"},
+		"account_gen.go": {"Account"},
+	} {
+		files, err := GenerateFiles(Config{
+			InputFilename: []string{filepath.FromSlash("bench/large.go")},
+			Structs:       structs,
+			PackageName:   "bench",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[0].Name = output
+		for _, f := range files {
+			filename := filepath.Join("bench", f.Name)
+			present, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(f.Data, present) {
+				t.Errorf("file %s is not up to date:\n%s", filename,
+					ShowDiff(string(present), string(f.Data)))
+			}
+		}
+	}
+}
+
+func TestRuntime(t *testing.T) {
+	cfg := Config{
+		InputFilename: []string{filepath.FromSlash("testdata/1.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "test",
+	}
+
+	// only used parts of runtime
+	b, err := Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{"func gencfFile(", "func gencfShow(", "type WizardStore ", `"path"`} {
+		if bytes.Contains(b, []byte(part)) {
+			t.Errorf("not used part of runtime is generated: %s", part)
+		}
+	}
+
+	// runtime in separate file by default
+	files, err := GenerateFiles(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[1].Name != RuntimeFilename {
+		t.Fatalf("runtime file is not generated: %d files", len(files))
+	}
+	if bytes.Contains(files[0].Data, []byte("func gencfWrite(")) {
+		t.Errorf("runtime is generated in Go source")
+	}
+	for _, part := range []string{"func gencfWrite(", "func gencfFile(", "func gencfShow(", "type WizardStore "} {
+		if !bytes.Contains(files[1].Data, []byte(part)) {
+			t.Errorf("runtime file have not part: %s", part)
+		}
+	}
+
+	// runtime in Go source
+	cfg.EmbedRuntime = true
+	if files, err = GenerateFiles(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || !bytes.Equal(files[0].Data, b) {
+		t.Errorf("runtime is not generated in Go source: %d files", len(files))
+	}
+}
+
+func TestTemplatesDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := Config{
+		InputFilename: []string{filepath.FromSlash("testdata/2.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "test",
+		TemplatesDir:  dir,
+	}
+
+	// folder without templates
+	if _, err := Generate(cfg); err == nil {
+		t.Errorf("folder without templates must be error")
+	}
+
+	// replace widget by default
+	override := `<textarea name="{{.Name}}">{{.Value}}</textarea>`
+	err = ioutil.WriteFile(filepath.Join(dir, "text.tmpl"), []byte(override), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`{{define "text"}}`+override+`{{end}}`)) {
+		t.Errorf("widget template is not replaced:\n%s", b)
+	}
+
+	// not valid template
+	err = ioutil.WriteFile(filepath.Join(dir, "text.tmpl"), []byte("{{if}}"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(cfg); err == nil {
+		t.Errorf("not valid template must be error")
+	}
+}
+
+// themeField is data of widget templates like in generated source
+type themeField struct {
+	Name, ID, Label, Help, Type, Widget string
+	Value                               interface{}
+	Error                               string
+	Required                            bool
+	Min, Max, Pattern                   string
+	Options                             []string
+	Accept                              string
+	HTML                                template.HTML
+	Items                               []template.HTML
+	New                                 template.HTML
+	Labels                              themeLabels
+}
+
+func (f themeField) DescribedBy() string {
+	return f.ID + "-help " + f.ID + "-error"
+}
+
+func (f themeField) Item(name string) themeItem {
+	return themeItem{Name: name, Labels: f.Labels}
+}
+
+// themeLabels is labels of controls of slice elements
+type themeLabels struct {
+	Item, Add, Remove, MoveUp, MoveDown, Move string
+}
+
+// themeItem is data of widget template slice-item
+type themeItem struct {
+	Name   string
+	Labels themeLabels
+}
+
+// labels is localized labels of controls of slice elements
+var labels = themeLabels{Item: "Daten", Add: "Neu", Remove: "Entfernen",
+	MoveUp: "Nach oben", MoveDown: "Nach unten", Move: "Verschieben"}
+
+// themeForm is data of form template like in generated source
+type themeForm struct {
+	Action, Method, Target, Enctype, ID, Class string
+	Hidden                                     map[string]string
+	Submit                                     string
+	Errors                                     map[string]string
+	Script, Nonce, Locale, Stylesheet          string
+	Label, Help                                string
+	HTML                                       template.HTML
+	JS                                         template.JS
+}
+
+// themeLayout is data of widget templates tabs and columns
+type themeLayout struct {
+	ID      string
+	Columns int
+	Groups  []themeField
+}
+
+// themeCondition is data of widget template showif
+type themeCondition struct {
+	Name, Values string
+	Show         bool
+	HTML         template.HTML
+}
+
+// themeWizard is data of widget template wizard
+type themeWizard struct {
+	ID, Back string
+	Step     int
+	Steps    []string
+	Review   bool
+	HTML     template.HTML
+}
+
+func TestThemes(t *testing.T) {
+	b, err := Generate(Config{
+		InputFilename: []string{filepath.FromSlash("testdata/2.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "test",
+		Theme:         "bootstrap5",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`class="form-control`)) {
+		t.Errorf("widget templates of theme are not used:\n%s", b)
+	}
+	if _, err := loadWidgets("unknown", ""); err == nil {
+		t.Errorf("not valid theme must be error")
+	}
+	plain, err := loadWidgets("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, theme := range Themes() {
+		t.Run(theme, func(t *testing.T) {
+			widgets, err := loadWidgets(theme, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(widgets) != len(plain) {
+				t.Errorf("amount of widgets: %d != %d", len(widgets), len(plain))
+			}
+			// templates of plain theme are not used by other themes
+			entries, err := themes.ReadDir(themesDir + "/" + theme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(plain) {
+				t.Errorf("amount of templates of theme: %d != %d", len(entries), len(plain))
+			}
+			tmpl := template.Must(template.New("").Parse(widgetsSource(widgets)))
+			for name := range widgets {
+				var data interface{} = themeField{
+					Name: "M.a", ID: "gencf-M-a", Label: "label", Help: "help",
+					Type: "string", Widget: "text", Value: "value", Error: "error",
+					Options: []string{"value"}, Accept: ".csv",
+					Items: []template.HTML{"item"}, New: "new", Labels: labels,
+				}
+				switch name {
+				case widgetForm:
+					data = themeForm{Method: "POST", Submit: "Submit",
+						Errors: map[string]string{"": "error"}, Label: "label", HTML: "html"}
+				case "slice-item":
+					data = themeItem{Name: "M.a[0]", Labels: labels}
+				case string(LayoutTabs), string(LayoutColumns):
+					data = themeLayout{ID: "gencf-M-a", Columns: 3, Groups: []themeField{
+						{ID: "gencf-M-_group-A", Label: "first", HTML: "error"},
+						{ID: "gencf-M-_group-B", Label: "second"},
+					}}
+				case defaultWidgetShowIf:
+					data = themeCondition{Name: "M.b", Values: "A|B", HTML: `<input id="gencf-M-a">error`}
+				case widgetWizard:
+					data = themeWizard{ID: "gencf-M-a", Back: "back", Step: 1,
+						Steps: []string{"first", "second", "review"}, HTML: "error"}
+				}
+				var buf bytes.Buffer
+				if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+					t.Errorf("widget %s: %v", name, err)
+					continue
+				}
+				out := buf.String()
+				exp := []string{"error", `id="gencf-M-a"`}
+				switch name {
+				case widgetForm:
+					exp = []string{"error", "label", "html", "Submit"}
+				case "slice-item":
+					exp = []string{`value="remove:M.a[0]"`, "data-gencf-up", "data-gencf-down", "data-gencf-drag",
+						`title="Nach oben"`, `title="Nach unten"`, `title="Entfernen"`, `title="Verschieben"`}
+				case defaultWidgetSlice, defaultWidgetSliceStruct:
+					exp = []string{`id="gencf-M-a"`, `data-gencf-slice="M.a"`, "data-gencf-add",
+						`data-gencf-item="0"`, "<template>", "new", "item", "data-gencf-index",
+						`title="Neu"`, "Daten", `value="remove:M.a[0]"`, `value="remove:M.a[__index__]"`,
+						`title="Nach oben"`}
+				case defaultWidgetFieldset:
+					exp = []string{`id="gencf-M-a"`, "label", "help"}
+				case string(LayoutTabs):
+					exp = []string{`id="gencf-M-a"`, "data-gencf-tabs", "data-gencf-tab",
+						`aria-controls="gencf-M-_group-B-panel"`, `id="gencf-M-_group-B-panel"`,
+						`id="gencf-M-_group-B"`, "second", "error"}
+				case string(LayoutColumns):
+					exp = []string{`id="gencf-M-a"`, `id="gencf-M-_group-B"`, "second", "error"}
+				case defaultWidgetShowIf:
+					exp = []string{`id="gencf-M-a"`, `data-gencf-showif="M.b"`, `data-gencf-values="A|B"`,
+						"hidden", "disabled", "error"}
+				case widgetWizard:
+					exp = []string{`id="gencf-M-a"`, `name="_wizard" value="0"`, `value="back"`,
+						"back", `aria-current="step"`, "second", "review", "error"}
+				}
+				for _, e := range exp {
+					if !strings.Contains(out, e) {
+						t.Errorf("widget %s have not %q:\n%s", name, e, out)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestImports(t *testing.T) {
+	g := generator{
+		cfg: Config{PackageName: "main"},
+		imports: map[string]bool{
+			"strconv":                          true,
+			"github.com/Konstantin8105/errors": true,
+			"net/http":                         true,
+			"fmt":                              true,
+			"html":                             true,
+		},
+	}
+	exp := `// Code generated by gensf. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+
+	"github.com/Konstantin8105/errors"
+)
+`
+	for i := 0; i < 10; i++ {
+		if act := string(g.header()); act != exp {
+			t.Fatalf("%s", ShowDiff(act, exp))
+		}
+	}
+}
+
 // ShowDiff will print two strings vertically next to each other so that line
 // differences are easier to read.
 func ShowDiff(a, b string) string {
@@ -85,3 +536,263 @@ func ShowDiff(a, b string) string {
 
 	return out
 }
+
+func TestParse(t *testing.T) {
+	forms, err := Parse(Config{
+		InputFilename: []string{filepath.FromSlash("testdata/4.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forms) != 1 {
+		t.Fatalf("amount of forms: %d", len(forms))
+	}
+	form := forms[0]
+	if form.Name != "TestStruct" || form.Doc != "Main struct of fields" {
+		t.Errorf("form: %s %q", form.Name, form.Doc)
+	}
+	if len(form.Fields) != 2 {
+		t.Fatalf("amount of fields: %d", len(form.Fields))
+	}
+	nested := form.Fields[1]
+	if nested.Kind != KindGroup || nested.Path != "NestedStruct" || len(nested.Fields) != 5 {
+		t.Fatalf("nested struct: %#v", nested)
+	}
+	deep := nested.Fields[4].Fields[0]
+	if deep.Kind != KindBasic || deep.Path != "NestedStruct.DoubleNested.some_value" ||
+		deep.Type != "float32" || deep.Label != "very deep field" || deep.Widget != "number" {
+		t.Errorf("deep field: %#v", deep)
+	}
+}
+
+func TestParseDoc(t *testing.T) {
+	forms, err := Parse(Config{
+		InputFilename: []string{filepath.FromSlash("testdata/10.got")},
+		Structs:       []string{"TestStruct", "Se"},
+		PackageName:   "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, exp := range []struct {
+		field       *Field
+		label, help string
+	}{
+		{forms[0].Fields[0], "A is some value", ""},
+		{forms[1].Fields[0], "Name of person.", "Name is written in latin letters. for example: Smith"},
+		{forms[1].Fields[1], "Age of person", "in years"},
+		{forms[1].Fields[2], "", ""},
+	} {
+		if exp.field.Label != exp.label || exp.field.Help != exp.help {
+			t.Errorf("%d: %q %q", i, exp.field.Label, exp.field.Help)
+		}
+	}
+}
+
+func TestExtractMessages(t *testing.T) {
+	messages, err := ExtractMessages(Config{
+		InputFilename: []string{filepath.FromSlash("testdata/8.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, message := range map[string]string{
+		"TestStruct":        "TestStruct with form tags",
+		"TestStruct.Age":    "Age of person",
+		"TestStruct.Name":   "Name of person",
+		"value is required": "value is required",
+		"value must be greater than or equal to 0": "value must be greater than or equal to 0",
+		"not valid integer value":                  "not valid integer value",
+		"Submit":                                   "Submit",
+	} {
+		if messages[key] != message {
+			t.Errorf("message %q: %q", key, messages[key])
+		}
+	}
+
+	// messages of runtime are present in generated source
+	src, err := Generate(Config{
+		InputFilename: []string{filepath.FromSlash("testdata/8.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range runtimeMessages {
+		if !bytes.Contains(src, []byte(fmt.Sprintf("%q", message))) {
+			t.Errorf("generated source have not message %q", message)
+		}
+	}
+
+	// messages of uploaded files
+	cfg := Config{
+		InputFilename: []string{filepath.FromSlash("testdata/9.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "main",
+	}
+	if messages, err = ExtractMessages(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if src, err = Generate(cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range fileMessages {
+		if messages[message] != message {
+			t.Errorf("message %q: %q", message, messages[message])
+		}
+		if !bytes.Contains(src, []byte(fmt.Sprintf("%q", message))) {
+			t.Errorf("generated source have not message %q", message)
+		}
+	}
+}
+
+func TestParseGroup(t *testing.T) {
+	forms, err := Parse(Config{
+		InputFilename: []string{filepath.FromSlash("testdata/11.got")},
+		Structs:       []string{"TestStruct", "Se"},
+		PackageName:   "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, ts := forms[0], forms[1]
+	if se.Layout != LayoutColumns || se.Columns != 3 || se.Doc != "Se is struct with groups in columns" {
+		t.Errorf("not valid directives: %#v", se)
+	}
+	if ts.Layout != LayoutTabs || ts.Columns != 0 {
+		t.Errorf("not valid directives: %#v", ts)
+	}
+	var names []string
+	for _, f := range ts.Fields {
+		names = append(names, f.Name)
+	}
+	if strings.Join(names, ",") != "Name,Solver,Nodes,Port,Host" {
+		t.Errorf("not valid order of fields: %v", names)
+	}
+	if f := ts.Fields[1]; f.Group != "Advanced" || f.Fields[0].Group != "Accuracy" {
+		t.Errorf("not valid groups: %#v", f)
+	}
+
+	for _, src := range []string{
+		"//gencf:layout=rows\ntype TestStruct struct{}",
+		"//gencf:columns=0\ntype TestStruct struct{}",
+		"//gencf:width=2\ntype TestStruct struct{}",
+		"type TestStruct struct{ A int `form:\"order=first\"` }",
+		"type TestStruct struct{ A int `form:\"group=\"` }",
+		"type TestStruct struct{ A int `form:\"showif=\"` }",
+		"type TestStruct struct{ A int `form:\"showif=B\"` }",
+		"type TestStruct struct{ A int `form:\"showif=A\"` }",
+		"type TestStruct struct{ A int `form:\"showif=B=1\"`; B []int }",
+		"type TestStruct struct{ A int; B struct{ C int } `form:\"showif=A\"` }",
+		"type TestStruct struct{ A int `form:\"showif=B\"`; B int `form:\"showif=A\"` }",
+		"type TestStruct struct{ A int `form:\"showif=B\"`; B int `form:\"showif=C\"` }",
+	} {
+		f, err := parser.ParseFile(token.NewFileSet(), "", "package test\n"+src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parseForm(f.Decls[0].(*ast.GenDecl), "TestStruct", nil); err == nil {
+			t.Errorf("not valid struct must be error:\n%s", src)
+		}
+	}
+}
+
+func TestWizardFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "wizard.go")
+	src := "package test\n\n//gencf:layout=wizard\ntype TestStruct struct {\n" +
+		"\tReport []byte `form:\"widget=file\"`\n}\n"
+	if err := ioutil.WriteFile(filename, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Generate(Config{
+		InputFilename: []string{filename},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "test",
+	})
+	if err == nil || !strings.Contains(err.Error(), "not supported by wizard") {
+		t.Errorf("wizard with uploaded files must be error: %v", err)
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	forms, err := Parse(Config{
+		InputFilename: []string{filepath.FromSlash("testdata/9.got")},
+		Structs:       []string{"TestStruct"},
+		PackageName:   "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := forms[0].Fields
+	for i, exp := range []struct {
+		kind Kind
+		typ  string
+	}{
+		{KindFile, "[]byte"},
+		{KindFile, "gencf.File"},
+		{KindSliceBasic, "byte"},
+	} {
+		if fields[i].Kind != exp.kind || fields[i].Type != exp.typ {
+			t.Errorf("field %s: %s %s", fields[i].Name, fields[i].Kind, fields[i].Type)
+		}
+	}
+	if accept := fields[0].Accept; len(accept) != 2 || accept[1] != ".csv" {
+		t.Errorf("accept: %v", accept)
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	for _, tc := range []struct {
+		tag   string
+		field Field
+		isErr bool
+	}{
+		{
+			tag:   "`json:\"a\"`",
+			field: Field{Widget: "text"},
+		},
+		{
+			tag: "`form:\"widget=number,required,min=0,max=150\"`",
+			field: Field{Widget: "number", Constraints: Constraints{
+				Required: true, Min: "0", Max: "150",
+			}},
+		},
+		{
+			tag:   "`form:\"pattern=[a-z]=\"`",
+			field: Field{Widget: "text", Constraints: Constraints{Pattern: "[a-z]="}},
+		},
+		{
+			tag:   "`form:\"unknown\"`",
+			isErr: true,
+		},
+		{
+			tag:   "`form:\"pattern=[a-z\"`",
+			isErr: true,
+		},
+	} {
+		t.Run(tc.tag, func(t *testing.T) {
+			f := Field{Widget: defaultWidget}
+			err := f.parseTag(tc.tag)
+			if (err != nil) != tc.isErr {
+				t.Fatalf("error: %v", err)
+			}
+			if tc.isErr {
+				return
+			}
+			if f.Widget != tc.field.Widget || f.Constraints != tc.field.Constraints {
+				t.Errorf("%#v != %#v", f, tc.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package gencf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format is format of generated document
+type Format string
+
+const (
+	// FormatJSON is JSON document
+	FormatJSON Format = "json"
+
+	// FormatYAML is YAML document
+	FormatYAML Format = "yaml"
+)
+
+// openapiVersion is version of OpenAPI specification
+const openapiVersion = "3.1.0"
+
+// openapiRef is prefix of references to struct schemas in OpenAPI document
+const openapiRef = "#/components/schemas/"
+
+// openapiFilename return name of OpenAPI document
+func openapiFilename(format Format) string {
+	return "openapi." + string(format)
+}
+
+// formSchemaName return name of schema with form values of struct
+func formSchemaName(name string) string {
+	return name + "Form"
+}
+
+// jsonMember is member of JSON object
+type jsonMember struct {
+	Name  string
+	Value interface{}
+}
+
+// jsonObject is JSON object with order of members
+type jsonObject []jsonMember
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, err := json.Marshal(m.Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteString(":")
+		b, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// openapi add OpenAPI document with components of structs
+func (g *generator) openapi(forms []*Form) error {
+	var schemas, bodies jsonObject
+	for _, form := range forms {
+		s, err := g.formSchema(form, openapiRef)
+		if err != nil {
+			return err
+		}
+		schemas = append(schemas, jsonMember{Name: form.Name, Value: s})
+
+		body, encoding, err := g.formBody(form)
+		if err != nil {
+			return err
+		}
+		schemas = append(schemas, jsonMember{Name: formSchemaName(form.Name), Value: body})
+
+		contentType := "application/x-www-form-urlencoded"
+		if g.hasFile(form, map[string]bool{}) {
+			contentType = "multipart/form-data"
+		}
+		media := jsonObject{
+			{Name: "schema", Value: jsonObject{{Name: "$ref", Value: openapiRef + formSchemaName(form.Name)}}},
+		}
+		if len(encoding) > 0 {
+			media = append(media, jsonMember{Name: "encoding", Value: encoding})
+		}
+		request := jsonObject{}
+		if form.Doc != "" {
+			request = append(request, jsonMember{Name: "description", Value: form.Doc})
+		}
+		request = append(request,
+			jsonMember{Name: "content", Value: jsonObject{{Name: contentType, Value: media}}},
+			jsonMember{Name: "required", Value: true})
+		bodies = append(bodies, jsonMember{Name: form.Name, Value: request})
+	}
+
+	doc := jsonObject{
+		{Name: "openapi", Value: openapiVersion},
+		{Name: "info", Value: jsonObject{
+			{Name: "title", Value: fmt.Sprintf("Forms of package %s", g.cfg.PackageName)},
+			{Name: "version", Value: "0.0.0"},
+		}},
+		{Name: "components", Value: jsonObject{
+			{Name: "schemas", Value: schemas},
+			{Name: "requestBodies", Value: bodies},
+		}},
+	}
+	b, err := json.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		return err
+	}
+	if g.cfg.OpenAPI == FormatYAML {
+		var buf bytes.Buffer
+		if err = jsonToYAML(&buf, json.NewDecoder(bytes.NewReader(b)), 0); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	} else {
+		b = append(b, '\n')
+	}
+	g.outputs = append(g.outputs, OutputFile{
+		Name: openapiFilename(g.cfg.OpenAPI),
+		Data: b,
+	})
+	return nil
+}
+
+// formBody return schema of form values with names of html inputs and
+// encoding of form values
+func (g *generator) formBody(form *Form) (s *jsonSchema, encoding jsonObject, err error) {
+	s = &jsonSchema{Type: "object", Description: form.Doc}
+	err = g.flatFields(s, &encoding, form.Fields, formName{literal: form.Name + "."},
+		map[string]bool{form.Name: true})
+	return
+}
+
+// formName is prefix of names of html inputs
+type formName struct {
+	// literal is prefix of name without slices
+	literal string
+
+	// pattern is regular expression of prefix of name with slices
+	pattern string
+}
+
+// add return prefix with suffix `s`
+func (n formName) add(s string) formName {
+	if n.pattern == "" {
+		return formName{literal: n.literal + s}
+	}
+	return formName{pattern: n.pattern + regexp.QuoteMeta(s)}
+}
+
+// slice return prefix of names of slice elements with name `s`
+func (n formName) slice(s string) formName {
+	pattern := n.pattern
+	if pattern == "" {
+		pattern = regexp.QuoteMeta(n.literal)
+	}
+	return formName{pattern: pattern + regexp.QuoteMeta(s) + `\[[0-9]+\]`}
+}
+
+// flatFields add properties of fields with names of html inputs
+func (g *generator) flatFields(s *jsonSchema, encoding *jsonObject, fields []*Field, prefix formName, visited map[string]bool) error {
+	add := func(name formName, fs *jsonSchema, f *Field) {
+		fs.Description = f.doc()
+		if name.pattern != "" {
+			s.PatternProps = append(s.PatternProps, schemaProperty{Name: "^" + name.pattern + "$", Schema: fs})
+			return
+		}
+		s.Properties = append(s.Properties, schemaProperty{Name: name.literal, Schema: fs})
+		if f.Constraints.Required && f.ShowIf == nil {
+			// hidden field is not required
+			s.Required = append(s.Required, name.literal)
+		}
+		var enc jsonObject
+		if f.Kind == KindFile {
+			// filename extensions are not media types
+			var types []string
+			for _, a := range f.Accept {
+				if !strings.HasPrefix(a, ".") {
+					types = append(types, a)
+				}
+			}
+			contentType := "application/octet-stream"
+			if len(types) > 0 {
+				contentType = strings.Join(types, ", ")
+			}
+			enc = jsonObject{{Name: "contentType", Value: contentType}}
+		} else {
+			enc = jsonObject{{Name: "style", Value: "form"}, {Name: "explode", Value: true}}
+		}
+		*encoding = append(*encoding, jsonMember{Name: name.literal, Value: enc})
+	}
+
+	for _, f := range fields {
+		switch f.Kind {
+		case KindBasic:
+			fs, err := basicSchema(f)
+			if err != nil {
+				return fmt.Errorf("field %s: %v", f.Path, err)
+			}
+			add(prefix.add(f.Path), fs, f)
+
+		case KindGroup:
+			if err := g.flatFields(s, encoding, f.Fields, prefix, visited); err != nil {
+				return err
+			}
+
+		case KindStruct, KindSliceStruct:
+			nested, ok := g.forms[f.Type]
+			if !ok || visited[f.Type] {
+				continue
+			}
+			name := prefix.add(f.Path + ".")
+			if f.Kind == KindSliceStruct {
+				name = prefix.slice(f.Path).add(".")
+			}
+			visited[f.Type] = true
+			err := g.flatFields(s, encoding, nested.Fields, name, visited)
+			delete(visited, f.Type)
+			if err != nil {
+				return err
+			}
+
+		case KindSliceBasic:
+			fs, err := basicSchema(f)
+			if err != nil {
+				return fmt.Errorf("field %s: %v", f.Path, err)
+			}
+			add(prefix.slice(f.Path), fs, f)
+
+		case KindFile:
+			add(prefix.add(f.Path), fileSchema(f), f)
+		}
+	}
+	return nil
+}
+
+// jsonToYAML write YAML document of JSON value from decoder with
+// indent `level`
+func jsonToYAML(w io.Writer, dec *json.Decoder, level int) error {
+	dec.UseNumber()
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	return yamlValue(w, dec, t, level)
+}
+
+// yamlValue write YAML of JSON value started by token `t`
+func yamlValue(w io.Writer, dec *json.Decoder, t json.Token, level int) error {
+	indent := strings.Repeat("  ", level)
+	switch v := t.(type) {
+	case json.Delim:
+		var empty, open string
+		if v == '{' {
+			empty, open = "{}", ""
+		} else {
+			empty, open = "[]", "- "
+		}
+		if !dec.More() {
+			fmt.Fprintf(w, " %s\n", empty)
+			_, err := dec.Token() // close
+			return err
+		}
+		if level > 0 || v == '[' {
+			fmt.Fprintf(w, "\n")
+		}
+		for dec.More() {
+			if v == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "%s%s:", indent, yamlKey(key.(string)))
+			} else {
+				fmt.Fprintf(w, "%s%s", indent, strings.TrimSpace(open))
+			}
+			t, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if err = yamlValue(w, dec, t, level+1); err != nil {
+				return err
+			}
+		}
+		_, err := dec.Token() // close
+		return err
+	case string:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, " %s\n", b)
+	case nil:
+		fmt.Fprintf(w, " null\n")
+	default:
+		fmt.Fprintf(w, " %v\n", v)
+	}
+	return nil
+}
+
+// yamlPlain is regular expression of keys without quotes in YAML
+var yamlPlain = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_.$/-]*$`)
+
+// yamlReserved is plain scalars of YAML 1.1, which are not strings
+var yamlReserved = map[string]bool{
+	"y": true, "yes": true, "n": true, "no": true,
+	"true": true, "false": true, "on": true, "off": true,
+	"null": true, "~": true,
+}
+
+// yamlKey return key of YAML mapping
+func yamlKey(key string) string {
+	if yamlPlain.MatchString(key) && !yamlReserved[strings.ToLower(key)] {
+		return key
+	}
+	return strconv.Quote(key)
+}
@@ -0,0 +1,289 @@
+package gencf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// schemaDialect is identifier of JSON Schema draft 2020-12
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaDefsRef is prefix of references to structs in JSON Schema
+const schemaDefsRef = "#/$defs/"
+
+// schemaFilename return name of JSON Schema file for struct
+func schemaFilename(name string) string {
+	return name + ".schema.json"
+}
+
+// jsonSchema is JSON Schema of struct or field
+type jsonSchema struct {
+	Schema           string           `json:"$schema,omitempty"`
+	Ref              string           `json:"$ref,omitempty"`
+	Title            string           `json:"title,omitempty"`
+	Description      string           `json:"description,omitempty"`
+	Type             string           `json:"type,omitempty"`
+	Enum             []interface{}    `json:"enum,omitempty"`
+	Minimum          *float64         `json:"minimum,omitempty"`
+	Maximum          *float64         `json:"maximum,omitempty"`
+	MinLength        *int             `json:"minLength,omitempty"`
+	MaxLength        *int             `json:"maxLength,omitempty"`
+	Pattern          string           `json:"pattern,omitempty"`
+	ContentEncoding  string           `json:"contentEncoding,omitempty"`
+	ContentMediaType string           `json:"contentMediaType,omitempty"`
+	Items            *jsonSchema      `json:"items,omitempty"`
+	Properties       schemaProperties `json:"properties,omitempty"`
+	PatternProps     schemaProperties `json:"patternProperties,omitempty"`
+	Required         []string         `json:"required,omitempty"`
+	Defs             schemaProperties `json:"$defs,omitempty"`
+}
+
+// schemaProperty is named JSON Schema
+type schemaProperty struct {
+	Name   string
+	Schema *jsonSchema
+}
+
+// schemaProperties is JSON object of schemas with order of fields
+type schemaProperties []schemaProperty
+
+func (ps schemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, p := range ps {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, err := json.Marshal(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteString(":")
+		b, err := json.Marshal(p.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// schema add JSON Schema file of struct
+func (g *generator) schema(form *Form) error {
+	s, err := g.formSchema(form, schemaDefsRef)
+	if err != nil {
+		return err
+	}
+	s.Schema = schemaDialect
+
+	// nested structs
+	var defs []string
+	g.schemaDefs(form, map[string]bool{form.Name: true}, &defs)
+	for _, name := range defs {
+		def, err := g.formSchema(g.forms[name], schemaDefsRef)
+		if err != nil {
+			return err
+		}
+		s.Defs = append(s.Defs, schemaProperty{Name: name, Schema: def})
+	}
+
+	b, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	g.outputs = append(g.outputs, OutputFile{
+		Name: schemaFilename(form.Name),
+		Data: append(b, '\n'),
+	})
+	return nil
+}
+
+// schemaDefs add names of structs used by form into defs
+func (g *generator) schemaDefs(form *Form, visited map[string]bool, defs *[]string) {
+	var fields func(fs []*Field)
+	fields = func(fs []*Field) {
+		for _, f := range fs {
+			switch f.Kind {
+			case KindGroup:
+				fields(f.Fields)
+			case KindStruct, KindSliceStruct:
+				nested, ok := g.forms[f.Type]
+				if !ok || visited[f.Type] {
+					continue
+				}
+				visited[f.Type] = true
+				*defs = append(*defs, f.Type)
+				g.schemaDefs(nested, visited, defs)
+			}
+		}
+	}
+	fields(form.Fields)
+}
+
+// formSchema return JSON Schema of struct. References to structs
+// start with `ref`.
+func (g *generator) formSchema(form *Form, ref string) (*jsonSchema, error) {
+	s, err := g.objectSchema(form.Fields, ref)
+	if err != nil {
+		return nil, fmt.Errorf("struct %s: %v", form.Name, err)
+	}
+	s.Title = form.Name
+	s.Description = form.Doc
+	return s, nil
+}
+
+// objectSchema return JSON Schema of object with fields
+func (g *generator) objectSchema(fields []*Field, ref string) (*jsonSchema, error) {
+	s := &jsonSchema{Type: "object"}
+	for _, f := range fields {
+		fs, err := g.fieldSchema(f, ref)
+		if err != nil {
+			return nil, err
+		}
+		if fs == nil {
+			continue
+		}
+		s.Properties = append(s.Properties, schemaProperty{Name: f.Name, Schema: fs})
+		if f.Constraints.Required && f.ShowIf == nil {
+			// hidden field is not required
+			s.Required = append(s.Required, f.Name)
+		}
+	}
+	return s, nil
+}
+
+// fieldSchema return JSON Schema of field. Nil is returned for not
+// supported field.
+func (g *generator) fieldSchema(f *Field, ref string) (s *jsonSchema, err error) {
+	switch f.Kind {
+	case KindBasic:
+		s, err = basicSchema(f)
+
+	case KindGroup:
+		s, err = g.objectSchema(f.Fields, ref)
+
+	case KindStruct:
+		s = g.refSchema(f.Type, ref)
+
+	case KindSliceBasic:
+		var items *jsonSchema
+		if items, err = basicSchema(f); err == nil {
+			s = &jsonSchema{Type: "array", Items: items}
+		}
+
+	case KindSliceStruct:
+		s = &jsonSchema{Type: "array", Items: g.refSchema(f.Type, ref)}
+
+	case KindFile:
+		data := fileSchema(f)
+		data.ContentEncoding = "base64"
+		if f.Type == "[]byte" {
+			s = data
+			break
+		}
+		s = &jsonSchema{Type: "object", Properties: schemaProperties{
+			{Name: "Name", Schema: &jsonSchema{Type: "string"}},
+			{Name: "ContentType", Schema: &jsonSchema{Type: "string"}},
+			{Name: "Data", Schema: data},
+		}}
+
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %v", f.Path, err)
+	}
+	s.Description = f.doc()
+	return s, nil
+}
+
+// refSchema return JSON Schema with reference to struct. For not generated
+// struct it is any object.
+func (g *generator) refSchema(name, ref string) *jsonSchema {
+	if _, ok := g.forms[name]; ok {
+		return &jsonSchema{Ref: ref + name}
+	}
+	return &jsonSchema{Type: "object"}
+}
+
+// fileSchema return JSON Schema of content of uploaded file
+func fileSchema(f *Field) *jsonSchema {
+	s := &jsonSchema{Type: "string"}
+	if len(f.Accept) == 1 && !strings.HasPrefix(f.Accept[0], ".") && !strings.HasSuffix(f.Accept[0], "*") {
+		s.ContentMediaType = f.Accept[0]
+	}
+	return s
+}
+
+// basicSchema return JSON Schema of value with Go basic type with
+// constraints of field
+func basicSchema(f *Field) (*jsonSchema, error) {
+	s := &jsonSchema{}
+	c := f.Constraints
+	switch basicWidget(f.Type) {
+	case defaultWidgetCheckbox:
+		s.Type = "boolean"
+	case defaultWidgetNumber:
+		s.Type = "number"
+		if f.Type != "float32" && f.Type != "float64" {
+			s.Type = "integer"
+		}
+		for _, limit := range []struct {
+			value string
+			ptr   **float64
+		}{
+			{c.Min, &s.Minimum},
+			{c.Max, &s.Maximum},
+		} {
+			if limit.value == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(limit.value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("not valid limit `%s`", limit.value)
+			}
+			*limit.ptr = &v
+		}
+		if s.Minimum == nil && (f.Type[0] == 'u' || f.Type == "byte") {
+			zero := 0.0
+			s.Minimum = &zero
+		}
+	default:
+		s.Type = "string"
+		for _, limit := range []struct {
+			value string
+			ptr   **int
+		}{
+			{c.Min, &s.MinLength},
+			{c.Max, &s.MaxLength},
+		} {
+			if limit.value == "" {
+				continue
+			}
+			v, err := strconv.Atoi(limit.value)
+			if err != nil {
+				return nil, fmt.Errorf("not valid length `%s`", limit.value)
+			}
+			*limit.ptr = &v
+		}
+		if c.Pattern != "" {
+			// pattern of html input match whole value
+			s.Pattern = "^(?:" + c.Pattern + ")$"
+		}
+	}
+	for _, opt := range f.Options {
+		var value interface{} = opt
+		if s.Type != "string" {
+			if err := json.Unmarshal([]byte(opt), &value); err != nil {
+				return nil, fmt.Errorf("not valid option `%s`", opt)
+			}
+		}
+		s.Enum = append(s.Enum, value)
+	}
+	return s, nil
+}
@@ -0,0 +1,24 @@
+package gencf
+
+import (
+	_ "embed"
+)
+
+// script is source of script for add elements of slices in browser
+//
+//go:embed script/gencf.js
+var script string
+
+// scriptRuntime is Go source of handler of script
+const scriptRuntime = `
+// ScriptHandler return handler of script for add elements of slices.
+// Url of handler is FormOptions.Script, for example:
+//
+//	http.Handle("/gencf.js", ScriptHandler())
+func ScriptHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		io.WriteString(w, gencfScript)
+	})
+}
+`
@@ -0,0 +1,315 @@
+// gencf.js is script for slices of forms generated by gencf.
+//
+// Slice is element with attributes id, data-gencf-slice (name of slice) and
+// data-gencf-count (amount of elements). Elements of slice are elements
+// with attribute data-gencf-item (index of element) in first child of
+// slice. Buttons with attributes:
+//
+//	data-gencf-add    add new element of slice from its template
+//	data-gencf-remove remove element
+//	data-gencf-up     move element up
+//	data-gencf-down   move element down
+//
+// Element may be moved by drag of element with attribute data-gencf-drag.
+// After any change elements are renumbered, so indexes in names of html
+// inputs are from 0 without gaps.
+//
+// Buttons are submit buttons with name "_action", so without script
+// elements are changed by form handler on server side.
+//
+// Groups of fields in tabs are element with attribute data-gencf-tabs with
+// hidden tablist of buttons with attribute data-gencf-tab and tab panels.
+// Value of attribute data-gencf-tab is CSS classes of selected and not
+// selected tab separated by "|". Without script all panels are shown.
+//
+// Conditional fields are element with attribute data-gencf-showif (name of
+// html input) and optional attribute data-gencf-values (values of input
+// separated by "|"). Element is shown if value of input is one of values
+// or, without values, value is not empty, "0" or "false". Hidden element
+// is disabled, so its inputs are not validated and not submitted.
+(function () {
+	var root = document.documentElement;
+	if (root.hasAttribute("data-gencf")) {
+		// script is already executed
+		return;
+	}
+	root.setAttribute("data-gencf", "");
+
+	// attributes with names of html inputs
+	var names = ["name", "data-gencf-slice", "data-gencf-showif"];
+
+	// attributes with ids of html elements
+	var ids = ["id", "for", "aria-describedby", "aria-controls", "aria-labelledby"];
+
+	// items return elements of slice
+	function items(slice) {
+		return Array.prototype.slice.call(slice.querySelectorAll(":scope > div > [data-gencf-item]"));
+	}
+
+	// renameID return id with prefix to instead of prefix from
+	function renameID(id, from, to) {
+		if (id === from || id.indexOf(from + "-") === 0) {
+			return to + id.slice(from.length);
+		}
+		return id;
+	}
+
+	// rename replace prefix of names and ids in element of slice
+	function rename(item, from, to, fromID, toID) {
+		item.querySelectorAll("*").forEach(function (el) {
+			names.forEach(function (attr) {
+				var value = el.getAttribute(attr);
+				if (value !== null && value.indexOf(from) === 0) {
+					el.setAttribute(attr, to + value.slice(from.length));
+				}
+			});
+			ids.forEach(function (attr) {
+				var value = el.getAttribute(attr);
+				if (value !== null) {
+					el.setAttribute(attr, value.split(" ").map(function (id) {
+						return renameID(id, fromID, toID);
+					}).join(" "));
+				}
+			});
+			if (el.getAttribute("name") === "_action") {
+				// action of submit button for work without script
+				var action = el.getAttribute("value") || "";
+				var colon = action.indexOf(":") + 1;
+				if (action.indexOf(from, colon) === colon) {
+					el.setAttribute("value", action.slice(0, colon) + to + action.slice(colon + from.length));
+				}
+			}
+			if (el.tagName === "TEMPLATE") {
+				// new elements of nested slices
+				el.innerHTML = el.innerHTML.split(from).join(to).split(fromID + "-").join(toID + "-");
+			}
+		});
+	}
+
+	// renumber set indexes of slice elements by order
+	function renumber(slice) {
+		var name = slice.getAttribute("data-gencf-slice");
+		var list = items(slice);
+		list.forEach(function (item, i) {
+			var index = item.getAttribute("data-gencf-item");
+			item.setAttribute("data-gencf-item", i);
+			item.querySelectorAll("[data-gencf-index]").forEach(function (el) {
+				if (el.closest("[data-gencf-item]") === item) {
+					el.textContent = i;
+				}
+			});
+			if (index !== String(i)) {
+				rename(item, name + "[" + index + "]", name + "[" + i + "]",
+					slice.id + "-" + index, slice.id + "-" + i);
+			}
+		});
+		slice.setAttribute("data-gencf-count", list.length);
+	}
+
+	// select show panel of tab and hide other panels
+	function select(tab) {
+		var tablist = tab.closest("[role=tablist]");
+		tablist.querySelectorAll(":scope > [data-gencf-tab]").forEach(function (el) {
+			var selected = el === tab;
+			var classes = el.getAttribute("data-gencf-tab").split("|");
+			(classes[0] || "").split(" ").filter(Boolean).forEach(function (c) {
+				el.classList.toggle(c, selected);
+			});
+			(classes[1] || "").split(" ").filter(Boolean).forEach(function (c) {
+				el.classList.toggle(c, !selected);
+			});
+			el.setAttribute("aria-selected", selected);
+			el.tabIndex = selected ? 0 : -1;
+			var panel = document.getElementById(el.getAttribute("aria-controls"));
+			if (panel) {
+				panel.hidden = !selected;
+			}
+		});
+	}
+
+	// tabs show tablists in element and select tabs with errors
+	function tabs(root) {
+		root.querySelectorAll("[data-gencf-tabs] > [role=tablist][hidden]").forEach(function (tablist) {
+			tablist.hidden = false;
+			var list = tablist.querySelectorAll(":scope > [data-gencf-tab]");
+			var tab = list[0];
+			for (var i = 0; i < list.length; i++) {
+				var panel = document.getElementById(list[i].getAttribute("aria-controls"));
+				if (panel && panel.querySelector("[aria-invalid=true]")) {
+					tab = list[i];
+					break;
+				}
+			}
+			if (tab) {
+				select(tab);
+			}
+		});
+	}
+
+	tabs(document);
+
+	// field with error of browser validation in hidden panel
+	document.addEventListener("invalid", function (event) {
+		var panel = event.target.closest && event.target.closest("[role=tabpanel][hidden]");
+		while (panel) {
+			var tab = document.getElementById(panel.getAttribute("aria-labelledby"));
+			if (tab) {
+				select(tab);
+			}
+			panel = panel.parentNode.closest("[role=tabpanel][hidden]");
+		}
+	}, true);
+
+	// value return value of html inputs with name in form of element or
+	// null for disabled inputs
+	function value(el, name) {
+		var scope = el.closest("form") || document;
+		var result = null;
+		scope.querySelectorAll("[name=\"" + CSS.escape(name) + "\"]").forEach(function (input) {
+			if (input.matches(":disabled")) {
+				return;
+			}
+			if (input.type === "checkbox") {
+				result = input.checked ? "true" : "false";
+			} else if (input.type !== "radio" || input.checked) {
+				result = input.value;
+			} else if (result === null) {
+				result = "";
+			}
+		});
+		return result;
+	}
+
+	// show show and hide conditional fields in element by values of
+	// html inputs
+	function show(root) {
+		var list = root.querySelectorAll("[data-gencf-showif]");
+		var changed = true;
+		for (var i = 0; changed && i <= list.length; i++) {
+			// field may be condition of other conditional field
+			changed = false;
+			list.forEach(function (el) {
+				var v = value(el, el.getAttribute("data-gencf-showif"));
+				var values = el.getAttribute("data-gencf-values");
+				var shown = v !== null && (values === null ?
+					["", "0", "false"].indexOf(v) < 0 :
+					values.split("|").indexOf(v) >= 0);
+				if (el.hidden === shown) {
+					el.hidden = !shown;
+					el.disabled = !shown;
+					changed = true;
+				}
+			});
+		}
+	}
+
+	show(document);
+
+	document.addEventListener("change", function () {
+		show(document);
+	});
+	document.addEventListener("input", function () {
+		show(document);
+	});
+
+	var actions = {
+		"data-gencf-add": function (slice) {
+			var html = slice.querySelector(":scope > template").innerHTML;
+			var list = slice.querySelector(":scope > div");
+			list.insertAdjacentHTML("beforeend", html);
+			tabs(list.lastElementChild);
+			show(list.lastElementChild);
+		},
+		"data-gencf-remove": function (slice, item) {
+			item.remove();
+		},
+		"data-gencf-up": function (slice, item) {
+			var prev = item.previousElementSibling;
+			if (prev) {
+				prev.before(item);
+			}
+		},
+		"data-gencf-down": function (slice, item) {
+			var next = item.nextElementSibling;
+			if (next) {
+				next.after(item);
+			}
+		},
+	};
+
+	document.addEventListener("click", function (event) {
+		var tab = event.target.closest("[data-gencf-tab]");
+		if (tab) {
+			select(tab);
+			return;
+		}
+		for (var attr in actions) {
+			var button = event.target.closest("[" + attr + "]");
+			if (!button) {
+				continue;
+			}
+			var slice = button.closest("[data-gencf-slice]");
+			if (!slice) {
+				return;
+			}
+			event.preventDefault();
+			actions[attr](slice, button.closest("[data-gencf-item]"));
+			renumber(slice);
+			return;
+		}
+	});
+
+	// drag and drop of slice elements
+	var dragged = null;
+
+	// target return element of slice with dragged element under event
+	function target(event) {
+		var item = dragged && event.target.closest("[data-gencf-item]");
+		while (item && item.parentNode !== dragged.parentNode) {
+			item = item.parentNode.closest("[data-gencf-item]");
+		}
+		return item;
+	}
+
+	document.addEventListener("pointerdown", function (event) {
+		var handle = event.target.closest("[data-gencf-drag]");
+		if (handle) {
+			handle.closest("[data-gencf-item]").setAttribute("draggable", "true");
+		}
+	});
+
+	document.addEventListener("dragstart", function (event) {
+		if (event.target.hasAttribute && event.target.hasAttribute("data-gencf-item")) {
+			dragged = event.target;
+			event.dataTransfer.effectAllowed = "move";
+		}
+	});
+
+	document.addEventListener("dragover", function (event) {
+		if (target(event)) {
+			event.preventDefault();
+		}
+	});
+
+	document.addEventListener("drop", function (event) {
+		var item = target(event);
+		if (!item || item === dragged) {
+			return;
+		}
+		event.preventDefault();
+		var index = Array.prototype.indexOf.bind(item.parentNode.children);
+		if (index(dragged) < index(item)) {
+			item.after(dragged);
+		} else {
+			item.before(dragged);
+		}
+		renumber(item.closest("[data-gencf-slice]"));
+	});
+
+	document.addEventListener("dragend", function () {
+		if (dragged) {
+			dragged.removeAttribute("draggable");
+			dragged = null;
+		}
+	});
+})();
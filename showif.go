@@ -0,0 +1,96 @@
+package gencf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// showIfRuntime is Go source of types and functions for conditions of
+// field visibility
+const showIfRuntime = `
+// gencfCondition is data of widget template showif
+type gencfCondition struct {
+	// Name of html input with value of condition
+	Name string
+
+	// Values of html input separated by "|", which show field. For empty
+	// values field is shown for not zero value.
+	Values string
+
+	// Show is true for shown field
+	Show bool
+
+	// HTML of field
+	HTML template.HTML
+}
+
+// gencfShow return true if value is one of values. For empty values
+// return true if value is not zero value.
+func gencfShow(value interface{}, values ...string) bool {
+	s := fmt.Sprint(value)
+	if len(values) == 0 {
+		switch s {
+		case "", "0", "false", "(0+0i)":
+			return false
+		}
+		return true
+	}
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+`
+
+// showIf return Go expression of condition of field visibility. Field
+// is hidden for hidden field of condition.
+func (g *generator) showIf(f *Field) string {
+	args := []string{"value." + f.ShowIf.Field}
+	for _, v := range f.ShowIf.Values {
+		args = append(args, fmt.Sprintf("%q", v))
+	}
+	cond := fmt.Sprintf("gencfShow(%s)", strings.Join(args, ", "))
+	if form, ok := g.forms[f.Struct]; ok {
+		if c := form.field(f.ShowIf.Field); c != nil && c.ShowIf != nil {
+			cond = g.showIf(c) + " && " + cond
+		}
+	}
+	return cond
+}
+
+// widgetData return name of widget template and Go expression with its
+// data for field. Field with condition of visibility is inside widget
+// showif. Returned false for not supported field.
+func (g *generator) widgetData(f *Field) (widget, data string, ok bool) {
+	widget = g.widgetTemplate(f)
+	if data, ok = g.fieldData(f); !ok || f.ShowIf == nil {
+		return
+	}
+	var buf bytes.Buffer
+	buf.WriteString("gencfCondition{\n")
+	buf.WriteString(fmt.Sprintf("Name: prefix + %q,\n", f.ShowIf.Field))
+	if len(f.ShowIf.Values) > 0 {
+		buf.WriteString(fmt.Sprintf("Values: %q,\n", strings.Join(f.ShowIf.Values, "|")))
+	}
+	buf.WriteString(fmt.Sprintf("Show: %s,\n", g.showIf(f)))
+	buf.WriteString(fmt.Sprintf("HTML: %s,\n", slot("return "+render(widget, data))))
+	buf.WriteString("}")
+	return defaultWidgetShowIf, buf.String(), true
+}
+
+// hasShowIf return true if form have fields with condition of visibility
+func hasShowIf(form *Form) bool {
+	var fields func(fs []*Field) bool
+	fields = func(fs []*Field) bool {
+		for _, f := range fs {
+			if f.ShowIf != nil || fields(f.Fields) {
+				return true
+			}
+		}
+		return false
+	}
+	return fields(form.Fields)
+}
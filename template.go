@@ -0,0 +1,119 @@
+package gencf
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// templateFilename return name of html template file for struct
+func templateFilename(name string) string {
+	return name + ".gen.tmpl"
+}
+
+// widgetsTemplateFilename is name of html template file with templates
+// of widgets
+const widgetsTemplateFilename = "gencf.gen.tmpl"
+
+// groupTemplateName return name of html template for group fields
+func groupTemplateName(f *Field) string {
+	return f.Struct + "." + f.Path
+}
+
+// formTemplate add html template of struct and Go source for execute it
+func (g *generator) formTemplate(form *Form) (err error) {
+	// html template
+	var tmpl bytes.Buffer
+	g.groupTemplate(&tmpl, form.Name, form.Fields)
+	g.templates = append(g.templates, OutputFile{
+		Name: templateFilename(form.Name),
+		Data: tmpl.Bytes(),
+	})
+
+	// data of template
+	g.source.WriteString(fmt.Sprintf(
+		"\nfunc (value %s) templateData(s *gencfSlots, prefix string, opts FormOptions) map[string]interface{} {\n", form.Name))
+	g.source.WriteString("\treturn ")
+	g.source.WriteString(g.templateData(form.Fields))
+	g.source.WriteString("\n}\n\n")
+
+	// WriteHtml
+	g.source.WriteString(fmt.Sprintf(`
+func (value %[1]s) writeHtml(w io.Writer, prefix string, opts FormOptions) (err error) {
+	return gencfRender(w, %[1]q, func(s *gencfSlots) interface{} {
+		return value.templateData(s, prefix, opts)
+	})
+}
+`, form.Name))
+
+	return g.formMethods(form)
+}
+
+// groupTemplate write html template with name `name` for fields.
+// Templates of nested groups are written after it.
+func (g *generator) groupTemplate(tmpl *bytes.Buffer, name string, fields []*Field) {
+	var groups []*Field
+	var sections []*section
+	tmpl.WriteString(fmt.Sprintf("{{define %q}}\n", name))
+	for _, b := range g.blocks(fields) {
+		if b.field == nil {
+			tmpl.WriteString(fmt.Sprintf("{{/* Group : %s */}}\n", b.title()))
+			tmpl.WriteString(fmt.Sprintf("{{template %q (index . %q)}}\n", g.blockWidget(b), b.key()))
+			sections = append(sections, b.groups...)
+			continue
+		}
+		f := b.field
+		tmpl.WriteString(fmt.Sprintf("{{/* Field : %s */}}\n", f.Path))
+		widget, _, ok := g.widgetData(f)
+		if !ok {
+			tmpl.WriteString(fmt.Sprintf("{{/* Type is not supported: %s */}}\n", f.Type))
+			continue
+		}
+		if f.Kind == KindGroup {
+			groups = append(groups, f)
+		}
+		tmpl.WriteString(fmt.Sprintf("{{template %q .%s}}\n", widget, f.Name))
+	}
+	tmpl.WriteString("{{end}}\n")
+
+	for _, f := range groups {
+		g.groupTemplate(tmpl, groupTemplateName(f), f.Fields)
+	}
+	for _, s := range sections {
+		g.groupTemplate(tmpl, s.name(), s.fields)
+	}
+}
+
+// templateData return Go expression with data of fields for html template
+func (g *generator) templateData(fields []*Field) string {
+	var buf bytes.Buffer
+	buf.WriteString("map[string]interface{}{\n")
+	for _, b := range g.blocks(fields) {
+		if b.field == nil {
+			buf.WriteString(fmt.Sprintf("%q: %s,\n", b.key(), g.blockData(b)))
+			continue
+		}
+		if _, data, ok := g.widgetData(b.field); ok {
+			buf.WriteString(fmt.Sprintf("%q: %s,\n", b.field.Name, data))
+		}
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// widgetsTemplate add html template file with widget templates and check
+// all html templates
+func (g *generator) widgetsTemplate() error {
+	g.templates = append([]OutputFile{{
+		Name: widgetsTemplateFilename,
+		Data: []byte(widgetsSource(g.widgets)),
+	}}, g.templates...)
+
+	t := template.New("")
+	for _, f := range g.templates {
+		if _, err := t.New(f.Name).Parse(string(f.Data)); err != nil {
+			return fmt.Errorf("cannot parse generated template %s: %v", f.Name, err)
+		}
+	}
+	return nil
+}
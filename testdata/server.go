@@ -1,7 +1,7 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"net/http"
 )
@@ -33,13 +33,11 @@ type M struct {
 	h []string
 }
 
-func handler(w http.ResponseWriter, r *http.Request) {
-	var m M
-	m.h = append(m.h, "--some--")
-	fmt.Fprintf(w, m.FormDefault("/resultOfM"))
-}
-
 func main() {
-	http.HandleFunc("/", handler)
+	http.Handle("/gencf.js", ScriptHandler())
+	http.Handle("/", NewMHandler(func(ctx context.Context, m *M) error {
+		log.Printf("%#v", *m)
+		return nil
+	}, WithFormOptions(FormOptions{Script: "/gencf.js"})))
 	log.Fatal(http.ListenAndServe(":9090", nil))
 }
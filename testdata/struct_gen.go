@@ -2,140 +2,232 @@
 
 package main
 
-import "fmt"
-
-func (value M) toHtml(prefix string) (out string) {
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+func (value M) writeHtml(w io.Writer, prefix string, opts FormOptions) (err error) {
 
 	// Field : a
-
-	out += fmt.Sprintf("\n<br><strong>parameter a</strong><br>\n")
-	out += fmt.Sprintf(
-		"\n<input type=\"text\" name=\"%sa\" value=\"%s\"><br>\n",
-		prefix, fmt.Sprintf("%v", value.a))
+	if err = gencfWrite(w, "number", gencfField{
+		Name:   prefix + "a",
+		ID:     gencfID(opts.ID, prefix+"a"),
+		Label:  gencfText(opts.Locale, "M.a", "parameter a"),
+		Type:   "int",
+		Widget: "number",
+		Value:  value.a,
+		Error:  opts.Errors[prefix+"a"],
+	}); err != nil {
+		return
+	}
 
 	// Field : b
-
-	out += fmt.Sprintf("\n<br><strong>parameter b</strong><br>\n")
-	out += fmt.Sprintf(
-		"\n<input type=\"text\" name=\"%sb\" value=\"%s\"><br>\n",
-		prefix, fmt.Sprintf("%v", value.b))
+	if err = gencfWrite(w, "number", gencfField{
+		Name:   prefix + "b",
+		ID:     gencfID(opts.ID, prefix+"b"),
+		Label:  gencfText(opts.Locale, "M.b", "parameter b"),
+		Type:   "uint8",
+		Widget: "number",
+		Value:  value.b,
+		Error:  opts.Errors[prefix+"b"],
+	}); err != nil {
+		return
+	}
 
 	// Field : c
-
-	out += fmt.Sprintf("\n<br><strong>parameter c with multiline comments</strong><br>\n")
-	out += fmt.Sprintf(
-		"\n<input type=\"text\" name=\"%sc\" value=\"%s\"><br>\n",
-		prefix, fmt.Sprintf("%v", value.c))
+	if err = gencfWrite(w, "number", gencfField{
+		Name:   prefix + "c",
+		ID:     gencfID(opts.ID, prefix+"c"),
+		Label:  gencfText(opts.Locale, "M.c", "parameter c with multiline comments"),
+		Type:   "float32",
+		Widget: "number",
+		Value:  value.c,
+		Error:  opts.Errors[prefix+"c"],
+	}); err != nil {
+		return
+	}
 
 	// Field : d
+	if err = gencfRender(w, "fieldset", func(s *gencfSlots) interface{} {
+		return gencfField{
+			Name:   prefix + "d",
+			ID:     gencfID(opts.ID, prefix+"d"),
+			Label:  gencfText(opts.Locale, "M.d", "d is anonymous struct"),
+			Type:   "struct",
+			Widget: "fieldset",
+			HTML: s.slot(func(w io.Writer) (err error) {
+
+				// Field : d.e
+				if err = gencfWrite(w, "number", gencfField{
+					Name:   prefix + "d.e",
+					ID:     gencfID(opts.ID, prefix+"d.e"),
+					Label:  gencfText(opts.Locale, "M.d.e", "internal value d.e"),
+					Type:   "uint16",
+					Widget: "number",
+					Value:  value.d.e,
+					Error:  opts.Errors[prefix+"d.e"],
+				}); err != nil {
+					return
+				}
+
+				// Field : d.f
+				if err = gencfWrite(w, "number", gencfField{
+					Name:   prefix + "d.f",
+					ID:     gencfID(opts.ID, prefix+"d.f"),
+					Label:  gencfText(opts.Locale, "M.d.f", "internal value d.f"),
+					Type:   "float64",
+					Widget: "number",
+					Value:  value.d.f,
+					Error:  opts.Errors[prefix+"d.f"],
+				}); err != nil {
+					return
+				}
+				return
+			}),
+		}
+	}); err != nil {
+		return
+	}
 
-	out += fmt.Sprintf("\n<br><strong>d is anonymous struct</strong><br>\n")
+	// Field : h
+	if err = gencfRender(w, "slice", func(s *gencfSlots) interface{} {
+		return gencfField{
+			Name:   prefix + "h",
+			ID:     gencfID(opts.ID, prefix+"h"),
+			Label:  gencfText(opts.Locale, "M.h", "h with slice"),
+			Type:   "string",
+			Widget: "text",
+			Value:  value.h,
+			Labels: gencfSliceText(opts.Locale),
+			Items: s.items(len(value.h), func(w io.Writer, i int) error {
+				name := fmt.Sprintf("%sh[%d]", prefix, i)
+				return gencfWrite(w, "text", gencfField{
+					Name:   name,
+					ID:     gencfID(opts.ID, name),
+					Type:   "string",
+					Widget: "text",
+					Value:  value.h[i],
+					Error:  opts.Errors[name],
+				})
+			}),
+			New: s.slot(func(w io.Writer) error {
+				return gencfWrite(w, "text", gencfField{
+					Name:   prefix + "h[__index__]",
+					ID:     gencfID(opts.ID, prefix+"h[__index__]"),
+					Type:   "string",
+					Widget: "text",
+					Value:  *new(string),
+				})
+			}),
+		}
+	}); err != nil {
+		return
+	}
+	return
+}
 
-	// Field : d.e
+func (value M) toHtml(prefix string, opts FormOptions) (out string) {
+	return string(gencfHtml(func(w io.Writer) error {
+		return value.writeHtml(w, prefix, opts)
+	}))
+}
 
-	out += fmt.Sprintf("\n<br><strong>internal value d.e</strong><br>\n")
-	out += fmt.Sprintf(
-		"\n<input type=\"text\" name=\"%sd.e\" value=\"%s\"><br>\n",
-		prefix, fmt.Sprintf("%v", value.d.e))
+func (value M) WriteHtml(w io.Writer) error {
+	return value.writeHtml(w, "M.", FormOptions{})
+}
 
-	// Field : d.f
+// ToHtml return html of struct fields. Error of widget template is
+// returned as text of html, use WriteHtml for get error.
+func (value M) ToHtml() (out string) {
+	return value.toHtml("M.", FormOptions{})
+}
 
-	out += fmt.Sprintf("\n<br><strong>internal value d.f</strong><br>\n")
-	out += fmt.Sprintf(
-		"\n<input type=\"text\" name=\"%sd.f\" value=\"%s\"><br>\n",
-		prefix, fmt.Sprintf("%v", value.d.f))
+func (value *M) decode(form url.Values, files map[string][]*multipart.FileHeader, prefix string, errs FormErrors) {
 
-	// Field : h
+	// Field : a
+	value.a = int(gencfParseInt(form.Get(prefix+"a"), prefix+"a", 0, errs))
 
-	out += fmt.Sprintf("\n<br><strong>h with slice</strong><br>\n")
+	// Field : b
+	value.b = uint8(gencfParseUint(form.Get(prefix+"b"), prefix+"b", 8, errs))
 
-	//
-	// Exist elements of field: h
-	//
-	for i := range value.h {
-		out += fmt.Sprintf("Data %d<br>\n", i)
-		out += fmt.Sprintf(
-			"\n<input type=\"text\" name=\"%sh[%d]\" value=\"%s\"><br>\n",
-			prefix, i, fmt.Sprintf("%v", value.h))
+	// Field : c
+	value.c = float32(gencfParseFloat(form.Get(prefix+"c"), prefix+"c", 32, errs))
+
+	// Field : d
+
+	// Field : d.e
+	value.d.e = uint16(gencfParseUint(form.Get(prefix+"d.e"), prefix+"d.e", 16, errs))
+
+	// Field : d.f
+	value.d.f = float64(gencfParseFloat(form.Get(prefix+"d.f"), prefix+"d.f", 64, errs))
+
+	// Field : h
+	value.h = nil
+	for _, i := range gencfIndexes(form, prefix+"h") {
+		name := fmt.Sprintf("%sh[%d]", prefix, i)
+		value.h = append(value.h, form.Get(name))
 	}
+}
 
-	//
-	// Array script of : h
-	//
-	out += "<script>\n"
-	out += fmt.Sprintf("var initValh = %d;\n", len(value.h))
-	out += "\n"
-	out += "function insertAfterh(elem, refElem) { \n"
-	out += "  console.log('Insert : ' + elem + ' in ' + refElem);\n"
-	out += "  var parent = refElem.parentNode; \n"
-	out += "  var next = refElem.nextSibling; \n"
-	out += "  if (next) { \n"
-	out += "    return parent.insertBefore(elem, next); \n"
-	out += "  } else { \n"
-	out += "    return parent.appendChild(elem); \n"
-	out += "  } \n"
-	out += "} \n"
-
-	out += "function createElh(context) { \n"
-	out += "	console.log('context : '+ context);\n"
-	out += "	// create label\n"
-	out += "	var txt = document.createElement(\"p\"); \n"
-	out += "    var id  = \"InputM.\"+initValh+\"h\" ;\n "
-	out += "	txt.id   = id;\n"
-	out += "	var node = document.createTextNode('Data '+ initValh);\n"
-	out += "	txt.appendChild(node);\n"
-	out += "	console.log(el);\n"
-	out += "	insertAfterh(txt,context); \n"
-	out += "	// create input\n"
-	out += "	var el = document.createElement(\"input\"); \n"
-	out += "	el.type = \"text\"; \n"
-	out += "	el.name = \"M.h[\"+initValh+\"]\"; \n"
-	out += "	var last = id;\n"
-	out += "	id = \"TextM.\"+initValh+\"h\" ;\n "
-	out += "	el.id   = id;\n"
-	out += "	console.log(el);\n"
-	out += "	insertAfterh(el, document.getElementById(last)); \n"
-	out += "	// incrementation\n"
-	out += "	initValh++; \n"
-	out += "	console.log(\"initVal = \" + initValh);\n"
-	out += "	// create br\n"
-	out += "	console.log('create label');\n"
-	out += "	var label = document.createElement(\"br\");\n"
-	out += "	label.id = 'breakLine' + initValh + 'h';\n"
-	out += "	console.log(label);\n"
-	out += "	insertAfterh (label, document.getElementById(id));\n"
-	out += " } \n"
-
-	out += "function addh() { \n"
-	out += "	console.log(\"initVal = \" + initValh);\n"
-	out += "	var name = 'breakLine' + initValh + 'h'; \n"
-	out += "	console.log('name of parent : ' + name);\n "
-	out += "	createElh(document.getElementById(name)); \n"
-	out += "	console.log(\"initVal = \" + initValh);\n"
-	out += "} \n"
-	out += "</script>\n"
-
-	out += "<button type=\"button\" OnClick=\"addh()\">+</button>\n"
-	out += fmt.Sprintf("<br id=\"breakLine%dh\">\n", len(value.h))
+func (value M) validate(prefix string, errs FormErrors) {
+}
 
-	return
+func (value *M) FromForm(form url.Values) error {
+	return value.fromForm(form, nil, true)
 }
 
-func (value M) ToHtml() (out string) {
-	return value.toHtml("M.")
+func (value *M) FromMultipartForm(form *multipart.Form) error {
+	return value.fromForm(form.Value, form.File, true)
 }
 
-func (value M) FormDefault(handlerName string) (out string) {
-	out += "<!DOCTYPE html>\n"
-	out += "<html>\n"
-	out += "<body>\n"
-	out += fmt.Sprintf("<form action=\"%s\" target=\"_blank\" method=\"GET\">\n", handlerName)
-	out += value.ToHtml()
-	out += "<input type=\"submit\" value=\"Submit\">"
-	out += "</form>"
-	out += "<br>\n"
-	out += "</body>\n"
-	out += "</html>\n"
+func (value *M) fromForm(form url.Values, files map[string][]*multipart.FileHeader, validate bool) error {
+	errs := FormErrors{}
+	value.decode(form, files, "M.", errs)
+	if validate {
+		value.validate("M.", errs)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
 
-	return
+func (value M) WriteForm(w io.Writer, opts FormOptions) error {
+	opts = gencfFormOptions(opts)
+	return gencfRender(w, "form", func(s *gencfSlots) interface{} {
+		return gencfForm{
+			FormOptions: opts,
+			Label:       gencfText(opts.Locale, "M", "M is some struct"),
+			Help:        gencfText(opts.Locale, "M#help", ""),
+			HTML: s.slot(func(w io.Writer) error {
+				return value.writeHtml(w, "M.", opts)
+			}),
+			JS: gencfScript,
+		}
+	})
+}
+
+func (value M) Form(opts FormOptions) (string, error) {
+	var buf bytes.Buffer
+	if err := value.WriteForm(&buf, opts); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func NewMHandler(onSubmit func(ctx context.Context, value *M) error, opts ...HandlerOption) http.Handler {
+	return gencfNewHandler(
+		func() gencfFormValue {
+			return new(M)
+		},
+		func(ctx context.Context, value gencfFormValue) error {
+			return onSubmit(ctx, value.(*M))
+		},
+		opts)
 }
@@ -0,0 +1,247 @@
+package gencf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// typescriptRuntime is TypeScript source of functions for convert form data
+const typescriptRuntime = `
+/** FormModel convert value to form data and back with names of html inputs */
+export interface FormModel<T> {
+  /** toFormData add value into form data */
+  toFormData(value: T, form?: FormData, prefix?: string): FormData;
+
+  /** fromFormData return value from form data */
+  fromFormData(form: FormData, prefix?: string): T;
+}
+/** gencfGet return string value of html input */
+function gencfGet(form: FormData, name: string): string {
+  const value = form.get(name);
+  return typeof value === "string" ? value : "";
+}
+
+/** gencfNumber return number value of html input */
+function gencfNumber(form: FormData, name: string): number {
+  const value = gencfGet(form, name);
+  return value === "" ? 0 : Number(value);
+}
+
+/** gencfBoolean return value of checkbox */
+function gencfBoolean(form: FormData, name: string): boolean {
+  return ["on", "1", "t", "T", "true", "TRUE", "True"].includes(gencfGet(form, name));
+}
+
+/** gencfFile return uploaded file of html input */
+function gencfFile(form: FormData, name: string): Blob | null {
+  const value = form.get(name);
+  return value instanceof Blob ? value : null;
+}
+
+/** gencfIndexes return sorted indexes of slice elements in form data */
+function gencfIndexes(form: FormData, name: string): number[] {
+  const indexes = new Set<number>();
+  form.forEach((_, key) => {
+    if (!key.startsWith(name + "[")) {
+      return;
+    }
+    const end = key.indexOf("]", name.length + 1);
+    const index = key.slice(name.length + 1, end);
+    if (end < 0 || !/^[0-9]+$/.test(index)) {
+      return;
+    }
+    indexes.add(Number(index));
+  });
+  return Array.from(indexes).sort((a, b) => a - b);
+}
+`
+
+// typescript add TypeScript module with interfaces of structs and
+// functions for convert them to form data and back
+func (g *generator) typescript(forms []*Form) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gensf. DO NOT EDIT.\n")
+	buf.WriteString(typescriptRuntime)
+	for _, form := range forms {
+		buf.WriteString("\n")
+		tsDoc(&buf, form.Doc, "")
+		buf.WriteString(fmt.Sprintf("export interface %s {\n", form.Name))
+		g.tsFields(&buf, form.Fields, "  ")
+		buf.WriteString("}\n")
+
+		buf.WriteString("\n")
+		buf.WriteString(fmt.Sprintf("/** %[1]s is form model of %[1]s */\n", form.Name))
+		buf.WriteString(fmt.Sprintf("export const %[1]s: FormModel<%[1]s> = {\n", form.Name))
+		buf.WriteString(fmt.Sprintf("  toFormData(value: %s, form: FormData = new FormData(), prefix: string = %q): FormData {\n",
+			form.Name, form.Name+"."))
+		for _, f := range form.Fields {
+			g.tsToFormData(&buf, f)
+		}
+		buf.WriteString("    return form;\n")
+		buf.WriteString("  },\n")
+		buf.WriteString("\n")
+		buf.WriteString(fmt.Sprintf("  fromFormData(form: FormData, prefix: string = %q): %s {\n",
+			form.Name+".", form.Name))
+		buf.WriteString("    return ")
+		g.tsObject(&buf, form.Fields, "    ")
+		buf.WriteString(";\n")
+		buf.WriteString("  },\n")
+		buf.WriteString("};\n")
+	}
+	g.outputs = append(g.outputs, OutputFile{
+		Name: g.cfg.TypeScript,
+		Data: buf.Bytes(),
+	})
+}
+
+// tsDoc write TSDoc comment with indent
+func tsDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	buf.WriteString(fmt.Sprintf("%s/** %s */\n", indent, strings.Replace(doc, "*/", "*\\/", -1)))
+}
+
+// tsFields write TypeScript properties of fields
+func (g *generator) tsFields(buf *bytes.Buffer, fields []*Field, indent string) {
+	for _, f := range fields {
+		var typ string
+		switch f.Kind {
+		case KindBasic:
+			typ = tsType(f.Type)
+		case KindSliceBasic:
+			typ = tsType(f.Type) + "[]"
+		case KindStruct:
+			typ = g.tsStruct(f.Type)
+		case KindSliceStruct:
+			typ = g.tsStruct(f.Type) + "[]"
+		case KindFile:
+			typ = "Blob | null"
+		case KindGroup:
+			tsDoc(buf, f.doc(), indent)
+			buf.WriteString(fmt.Sprintf("%s%s: {\n", indent, f.Name))
+			g.tsFields(buf, f.Fields, indent+"  ")
+			buf.WriteString(fmt.Sprintf("%s};\n", indent))
+			continue
+		default:
+			continue
+		}
+		tsDoc(buf, f.doc(), indent)
+		buf.WriteString(fmt.Sprintf("%s%s: %s;\n", indent, f.Name, typ))
+	}
+}
+
+// tsType return TypeScript type of Go basic type
+func tsType(typ string) string {
+	switch basicWidget(typ) {
+	case defaultWidgetCheckbox:
+		return "boolean"
+	case defaultWidgetNumber:
+		return "number"
+	}
+	return "string"
+}
+
+// tsStruct return TypeScript type of struct. For not generated struct
+// it is any object.
+func (g *generator) tsStruct(name string) string {
+	if _, ok := g.forms[name]; ok {
+		return name
+	}
+	return "Record<string, unknown>"
+}
+
+// tsToFormData write TypeScript code for add value of field into form data
+func (g *generator) tsToFormData(buf *bytes.Buffer, f *Field) {
+	const indent = "    "
+	name := fmt.Sprintf("prefix + %q", f.Path)
+	switch f.Kind {
+	case KindBasic:
+		buf.WriteString(fmt.Sprintf("%sform.append(%s, String(value.%s));\n", indent, name, f.Path))
+
+	case KindGroup:
+		for _, ff := range f.Fields {
+			g.tsToFormData(buf, ff)
+		}
+
+	case KindStruct:
+		if _, ok := g.forms[f.Type]; !ok {
+			break
+		}
+		buf.WriteString(fmt.Sprintf("%s%s.toFormData(value.%s, form, prefix + %q);\n",
+			indent, f.Type, f.Path, f.Path+"."))
+
+	case KindFile:
+		buf.WriteString(fmt.Sprintf("%sif (value.%s) {\n", indent, f.Path))
+		buf.WriteString(fmt.Sprintf("%s  form.append(%s, value.%s);\n", indent, name, f.Path))
+		buf.WriteString(fmt.Sprintf("%s}\n", indent))
+
+	case KindSliceBasic:
+		buf.WriteString(fmt.Sprintf("%svalue.%s.forEach((item, i) => form.append(`${prefix}%s[${i}]`, String(item)));\n",
+			indent, f.Path, f.Path))
+
+	case KindSliceStruct:
+		if _, ok := g.forms[f.Type]; !ok {
+			break
+		}
+		buf.WriteString(fmt.Sprintf("%svalue.%s.forEach((item, i) => %s.toFormData(item, form, `${prefix}%s[${i}].`));\n",
+			indent, f.Path, f.Type, f.Path))
+	}
+}
+
+// tsObject write TypeScript object literal of fields from form data
+func (g *generator) tsObject(buf *bytes.Buffer, fields []*Field, indent string) {
+	buf.WriteString("{\n")
+	for _, f := range fields {
+		name := fmt.Sprintf("prefix + %q", f.Path)
+		var value string
+		switch f.Kind {
+		case KindBasic:
+			value = tsParse(f.Type, name)
+
+		case KindGroup:
+			buf.WriteString(fmt.Sprintf("%s  %s: ", indent, f.Name))
+			g.tsObject(buf, f.Fields, indent+"  ")
+			buf.WriteString(",\n")
+			continue
+
+		case KindStruct:
+			value = "{}"
+			if _, ok := g.forms[f.Type]; ok {
+				value = fmt.Sprintf("%s.fromFormData(form, prefix + %q)", f.Type, f.Path+".")
+			}
+
+		case KindFile:
+			value = fmt.Sprintf("gencfFile(form, %s)", name)
+
+		case KindSliceBasic:
+			value = fmt.Sprintf("gencfIndexes(form, %s).map((i) => %s)",
+				name, tsParse(f.Type, fmt.Sprintf("`${prefix}%s[${i}]`", f.Path)))
+
+		case KindSliceStruct:
+			value = "[]"
+			if _, ok := g.forms[f.Type]; ok {
+				value = fmt.Sprintf("gencfIndexes(form, %s).map((i) => %s.fromFormData(form, `${prefix}%s[${i}].`))",
+					name, f.Type, f.Path)
+			}
+
+		default:
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("%s  %s: %s,\n", indent, f.Name, value))
+	}
+	buf.WriteString(indent + "}")
+}
+
+// tsParse return TypeScript expression of value with Go basic type `typ`
+// from html input with name expression `name`
+func tsParse(typ, name string) string {
+	switch tsType(typ) {
+	case "boolean":
+		return fmt.Sprintf("gencfBoolean(form, %s)", name)
+	case "number":
+		return fmt.Sprintf("gencfNumber(form, %s)", name)
+	}
+	return fmt.Sprintf("gencfGet(form, %s)", name)
+}
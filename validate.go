@@ -0,0 +1,235 @@
+package gencf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/Konstantin8105/errors"
+)
+
+// validateRuntime is Go source of functions for validate values
+const validateRuntime = `
+// gencfPatterns is compiled regular expressions by patterns
+var gencfPatterns sync.Map
+
+// gencfMatch return true if value match pattern
+func gencfMatch(pattern, s string) bool {
+	re, ok := gencfPatterns.Load(pattern)
+	if !ok {
+		re, _ = gencfPatterns.LoadOrStore(pattern, regexp.MustCompile("^(?:"+pattern+")$"))
+	}
+	return re.(*regexp.Regexp).MatchString(s)
+}
+`
+
+// validate write Go source of method validate for struct
+func (g *generator) validate(form *Form) error {
+	et := errors.New(fmt.Sprintf("Constraints of struct %s", form.Name))
+	g.source.WriteString(fmt.Sprintf(
+		"\nfunc (value %s) validate(prefix string, errs FormErrors) {\n", form.Name))
+	for _, f := range form.Fields {
+		if err := g.validateField(&g.source, f); err != nil {
+			et.Add(err)
+		}
+	}
+	g.source.WriteString("}\n")
+	if et.IsError() {
+		return et
+	}
+	return nil
+}
+
+// validateField write Go code for check constraints of field
+func (g *generator) validateField(source *bytes.Buffer, f *Field) error {
+	if f.ShowIf != nil {
+		// constraints of hidden field are not checked
+		c := *f
+		c.ShowIf = nil
+		var checks bytes.Buffer
+		if err := g.validateField(&checks, &c); err != nil {
+			return err
+		}
+		if checks.Len() > 0 {
+			source.WriteString(fmt.Sprintf("\tif %s {\n", g.showIf(f)))
+			source.WriteString(checks.String())
+			source.WriteString("\t}\n")
+		}
+		return nil
+	}
+	switch f.Kind {
+	case KindBasic:
+		return g.checks(source, f, "value."+f.Path, fmt.Sprintf("prefix+%q", f.Path))
+
+	case KindGroup:
+		et := errors.New(fmt.Sprintf("Field %s", f.Path))
+		for _, ff := range f.Fields {
+			if err := g.validateField(source, ff); err != nil {
+				et.Add(err)
+			}
+		}
+		if et.IsError() {
+			return et
+		}
+
+	case KindStruct:
+		source.WriteString(fmt.Sprintf("\tvalue.%s.validate(prefix+%q, errs)\n",
+			f.Path, f.Path+"."))
+
+	case KindFile:
+		value := "value." + f.Path
+		if f.Type != "[]byte" {
+			value += ".Data"
+		}
+		return g.fileChecks(source, f, value, fmt.Sprintf("prefix+%q", f.Path))
+
+	case KindSliceBasic:
+		var checks bytes.Buffer
+		if err := g.checks(&checks, f, fmt.Sprintf("value.%s[i]", f.Path), "name"); err != nil {
+			return err
+		}
+		if checks.Len() == 0 {
+			break
+		}
+		g.addImport("fmt")
+		source.WriteString(fmt.Sprintf("\tfor i := range value.%s {\n", f.Path))
+		source.WriteString(fmt.Sprintf("name := fmt.Sprintf(\"%%s%s[%%d]\", prefix, i)\n", f.Path))
+		source.Write(checks.Bytes())
+		source.WriteString("}\n")
+
+	case KindSliceStruct:
+		g.addImport("fmt")
+		source.WriteString(fmt.Sprintf("\tfor i := range value.%s {\n", f.Path))
+		source.WriteString(fmt.Sprintf(
+			"value.%[1]s[i].validate(fmt.Sprintf(\"%%s%[1]s[%%d].\", prefix, i), errs)\n", f.Path))
+		source.WriteString("}\n")
+	}
+	return nil
+}
+
+// checks write Go code for check constraints of value with Go basic type.
+// Value and name of html input are Go expressions.
+func (g *generator) checks(source *bytes.Buffer, f *Field, value, name string) error {
+	c := f.Constraints
+	check := func(cond, message string) {
+		g.message(message, message)
+		source.WriteString(fmt.Sprintf("\tif %s {\n\t\tgencfError(errs, %s, %q)\n\t}\n",
+			cond, name, message))
+	}
+
+	isString := f.Type == "string"
+	isNumber := basicWidget(f.Type) == defaultWidgetNumber
+
+	if c.Required {
+		switch {
+		case isString:
+			check(value+` == ""`, "value is required")
+		case f.Type == "bool":
+			check("!"+value, "value is required")
+		default:
+			check(value+" == 0", "value is required")
+		}
+	}
+
+	for _, limit := range []struct {
+		name, value, op, length, number string
+	}{
+		{"min", c.Min, "<", "length must be at least %d", "value must be greater than or equal to %s"},
+		{"max", c.Max, ">", "length must be at most %d", "value must be less than or equal to %s"},
+	} {
+		if limit.value == "" {
+			continue
+		}
+		switch {
+		case isString:
+			n, err := strconv.Atoi(limit.value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("Field %s: not valid %s length `%s`", f.Path, limit.name, limit.value)
+			}
+			g.addImport("unicode/utf8")
+			check(fmt.Sprintf("utf8.RuneCountInString(%s) %s %d", value, limit.op, n),
+				fmt.Sprintf(limit.length, n))
+		case isNumber:
+			v, err := strconv.ParseFloat(limit.value, 64)
+			if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+				return fmt.Errorf("Field %s: not valid %s value `%s`", f.Path, limit.name, limit.value)
+			}
+			s := strconv.FormatFloat(v, 'g', -1, 64)
+			check(fmt.Sprintf("float64(%s) %s %s", value, limit.op, s),
+				fmt.Sprintf(limit.number, s))
+		default:
+			return fmt.Errorf("Field %s: %s is not allowable for type %s", f.Path, limit.name, f.Type)
+		}
+	}
+
+	if c.Pattern != "" {
+		if !isString {
+			return fmt.Errorf("Field %s: pattern is not allowable for type %s", f.Path, f.Type)
+		}
+		check(fmt.Sprintf("%s != \"\" && !gencfMatch(%q, %s)", value, c.Pattern, value),
+			"value does not match pattern")
+	}
+	return nil
+}
+
+// fileChecks write Go code for check constraints of uploaded file
+// with content `value`
+func (g *generator) fileChecks(source *bytes.Buffer, f *Field, value, name string) error {
+	c := f.Constraints
+	check := func(cond, message string) {
+		g.message(message, message)
+		source.WriteString(fmt.Sprintf("\tif %s {\n\t\tgencfError(errs, %s, %q)\n\t}\n",
+			cond, name, message))
+	}
+	if c.Required {
+		check(fmt.Sprintf("len(%s) == 0", value), "value is required")
+	}
+	for _, limit := range []struct {
+		name, value, op, message string
+	}{
+		{"min", c.Min, "<", "file size must be at least %d bytes"},
+		{"max", c.Max, ">", "file size must be at most %d bytes"},
+	} {
+		if limit.value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(limit.value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("Field %s: not valid %s size `%s`", f.Path, limit.name, limit.value)
+		}
+		check(fmt.Sprintf("len(%s) %s %d", value, limit.op, n), fmt.Sprintf(limit.message, n))
+	}
+	if c.Pattern != "" {
+		return fmt.Errorf("Field %s: pattern is not allowable for file", f.Path)
+	}
+	return nil
+}
+
+// fromForm write Go source of methods FromForm and FromMultipartForm
+// for struct
+func (g *generator) fromForm(form *Form) {
+	g.addImport("mime/multipart")
+	g.addImport("net/url")
+	g.source.WriteString(fmt.Sprintf(`
+func (value *%[1]s) FromForm(form url.Values) error {
+	return value.fromForm(form, nil, true)
+}
+
+func (value *%[1]s) FromMultipartForm(form *multipart.Form) error {
+	return value.fromForm(form.Value, form.File, true)
+}
+
+func (value *%[1]s) fromForm(form url.Values, files map[string][]*multipart.FileHeader, validate bool) error {
+	errs := FormErrors{}
+	value.decode(form, files, %[2]q, errs)
+	if validate {
+		value.validate(%[2]q, errs)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+`, form.Name, form.Name+"."))
+}
@@ -0,0 +1,551 @@
+package gencf
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// themes are html templates of widgets in folders `themes/<theme>`.
+// Name of template is filename without extension.
+//
+//go:embed themes
+var themes embed.FS
+
+// themesDir is folder of themes
+const themesDir = "themes"
+
+// ThemePlain is theme by default with html without CSS classes
+const ThemePlain = "plain"
+
+// widgetExt is extension of widget template files
+const widgetExt = ".tmpl"
+
+// widgetForm is name of widget template for form wrapper
+const widgetForm = "form"
+
+// Themes return names of themes
+func Themes() (names []string) {
+	entries, err := themes.ReadDir(themesDir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// loadWidgets return html templates of widgets by names. Templates of
+// theme replace templates of plain theme. Templates from folder `dir`
+// replace templates of theme or add new widgets.
+func loadWidgets(theme, dir string) (widgets map[string]string, err error) {
+	widgets = map[string]string{}
+
+	if theme == "" {
+		theme = ThemePlain
+	}
+	found := false
+	for _, name := range Themes() {
+		found = found || name == theme
+	}
+	if !found {
+		return nil, fmt.Errorf("not valid theme: %s. Themes: %s",
+			theme, strings.Join(Themes(), ", "))
+	}
+	for _, name := range []string{ThemePlain, theme} {
+		entries, err := themes.ReadDir(path.Join(themesDir, name))
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !strings.HasSuffix(e.Name(), widgetExt) {
+				continue
+			}
+			b, err := themes.ReadFile(path.Join(themesDir, name, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+			widgets[strings.TrimSuffix(e.Name(), widgetExt)] = string(b)
+		}
+	}
+
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*"+widgetExt))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("folder `%s` have not templates `*%s`", dir, widgetExt)
+		}
+		for _, filename := range files {
+			b, err := ioutil.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+			widgets[strings.TrimSuffix(filepath.Base(filename), widgetExt)] = string(b)
+		}
+	}
+
+	// check templates
+	if _, err = template.New("").Parse(widgetsSource(widgets)); err != nil {
+		return nil, fmt.Errorf("cannot parse widget templates: %v", err)
+	}
+	return widgets, nil
+}
+
+// widgetsSource return html template with all widget templates
+func widgetsSource(widgets map[string]string) string {
+	var names []string
+	for name := range widgets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString(fmt.Sprintf("{{define %q}}%s{{end}}\n", name, widgets[name]))
+	}
+	return buf.String()
+}
+
+// widgetTemplate return name of widget template for field
+func (g *generator) widgetTemplate(f *Field) string {
+	if f.Kind == KindSliceBasic {
+		return defaultWidgetSlice
+	}
+	return g.itemTemplate(f)
+}
+
+// itemTemplate return name of widget template for field or for element
+// of slice with Go basic type
+func (g *generator) itemTemplate(f *Field) string {
+	if _, ok := g.widgets[f.Widget]; ok {
+		return f.Widget
+	}
+	switch f.Kind {
+	case KindGroup, KindStruct:
+		return defaultWidgetFieldset
+	case KindSliceStruct:
+		return defaultWidgetSliceStruct
+	case KindFile:
+		return defaultWidgetFile
+	}
+	// html input with type Widget
+	return defaultWidget
+}
+
+// widgetsRuntime is Go source of types and functions for execute widget
+// templates
+const widgetsRuntime = `
+// gencfField is data of widget template
+type gencfField struct {
+	// Name of html input, for example: "M.d.e"
+	Name string
+
+	// ID of html element, for example: "gencf-M-d-e"
+	ID string
+
+	// Label is first sentence of documentation of field
+	Label string
+
+	// Help is description of field
+	Help string
+
+	// Type is Go type of field or of slice element
+	Type string
+
+	// Widget is name of widget, for not exist widget template it is
+	// type of html input
+	Widget string
+
+	// Value of field
+	Value interface{}
+
+	// Error of value
+	Error string
+
+	// Constraints of value
+	Required bool
+	Min      string
+	Max      string
+	Pattern  string
+
+	// Options of widget select
+	Options []string
+
+	// Accept is allowable MIME types or filename extensions of widget file
+	// separated by comma
+	Accept string
+
+	// HTML is html of nested struct fields
+	HTML template.HTML
+
+	// Items is html of slice elements
+	Items []template.HTML
+
+	// New is html of new slice element. Index of element is "__index__".
+	New template.HTML
+
+	// Labels of controls of slice elements
+	Labels gencfSliceLabels
+}
+
+// gencfSliceLabels is labels of controls of slice elements
+type gencfSliceLabels struct {
+	// Item is label of slice element before its index
+	Item string
+
+	// Labels of buttons
+	Add, Remove, MoveUp, MoveDown, Move string
+}
+
+// gencfSliceText return labels of controls of slice elements for locale
+func gencfSliceText(locale string) gencfSliceLabels {
+	return gencfSliceLabels{
+		Item:     gencfText(locale, "Data", "Data"),
+		Add:      gencfText(locale, "Add", "Add"),
+		Remove:   gencfText(locale, "Remove", "Remove"),
+		MoveUp:   gencfText(locale, "Move up", "Move up"),
+		MoveDown: gencfText(locale, "Move down", "Move down"),
+		Move:     gencfText(locale, "Move", "Move"),
+	}
+}
+
+// gencfItem is data of widget template slice-item
+type gencfItem struct {
+	// Name of slice element, for example: "M.d[0]"
+	Name string
+
+	// Labels of controls of slice elements
+	Labels gencfSliceLabels
+}
+
+// Item return data of widget template slice-item for slice element with
+// name
+func (f gencfField) Item(name string) gencfItem {
+	return gencfItem{Name: name, Labels: f.Labels}
+}
+
+// gencfLayout is data of widget templates for groups of fields in tabs
+// and columns
+type gencfLayout struct {
+	// ID of html element
+	ID string
+
+	// Columns is amount of columns
+	Columns int
+
+	// Groups of fields with label and html of fields
+	Groups []gencfField
+}
+
+// FormOptions is options of html form
+type FormOptions struct {
+	// Action is url of form handler
+	Action string
+
+	// Method of form. By default: "POST"
+	Method string
+
+	// Target of form, for example: "_blank". By default form is
+	// submitted into same window.
+	Target string
+
+	// Enctype of form, for example: "multipart/form-data"
+	Enctype string
+
+	// ID of form. It is prefix of ids of html elements in form, so it
+	// must be unique on html page with several forms.
+	ID string
+
+	// Class is CSS classes of form separated by space
+	Class string
+
+	// Hidden is values of hidden inputs by names
+	Hidden map[string]string
+
+	// Submit is label of submit button. By default: "Submit"
+	Submit string
+
+	// Errors of form values for show near html inputs
+	Errors FormErrors
+
+	// Script is url of script for add elements of slices, see
+	// ScriptHandler. By default script is not added.
+	Script string
+
+	// Nonce is nonce of Content-Security-Policy for script. For empty
+	// Script script is inline.
+	Nonce string
+
+	// Locale of labels, help and errors, for example "de-AT", see
+	// AddCatalog. By default documentation of structs is used.
+	Locale string
+
+	// Stylesheet is url of CSS stylesheet of page, for example
+	// stylesheet of CSS framework of theme
+	Stylesheet string
+}
+
+// gencfFormOptions return form options with values by default
+func gencfFormOptions(opts FormOptions) FormOptions {
+	if opts.Method == "" {
+		opts.Method = "POST"
+	}
+	if opts.Submit == "" {
+		opts.Submit = gencfText(opts.Locale, "Submit", "Submit")
+	}
+	opts.Errors = opts.Errors.Localize(opts.Locale)
+	return opts
+}
+
+// gencfForm is data of form widget template
+type gencfForm struct {
+	FormOptions
+
+	// Label is first sentence of documentation of struct
+	Label string
+
+	// Help is description of struct
+	Help string
+
+	// HTML is html of struct fields
+	HTML template.HTML
+
+	// JS is inline script for add elements of slices
+	JS template.JS
+}
+
+// DescribedBy return ids of html elements with help and error of field
+// separated by space
+func (f gencfField) DescribedBy() string {
+	var ids []string
+	if f.Help != "" {
+		ids = append(ids, f.ID+"-help")
+	}
+	if f.Error != "" {
+		ids = append(ids, f.ID+"-error")
+	}
+	return strings.Join(ids, " ")
+}
+
+// gencfID return id of html element for html input with name. Id is
+// unique on html page for unique prefix. By default prefix is "gencf".
+func gencfID(prefix, name string) string {
+	if prefix == "" {
+		prefix = "gencf"
+	}
+	var buf bytes.Buffer
+	buf.WriteString(prefix)
+	buf.WriteByte('-')
+	for _, r := range name {
+		switch {
+		case r == '.' || r == '[':
+			buf.WriteByte('-')
+		case r == ']':
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			buf.WriteRune(r)
+		default:
+			fmt.Fprintf(&buf, "_%x_", r)
+		}
+	}
+	return buf.String()
+}
+
+// gencfWrite write result of widget template execution into w
+func gencfWrite(w io.Writer, name string, data interface{}) error {
+	return gencfTemplates.ExecuteTemplate(w, name, data)
+}
+
+// gencfHtml return html written by function write.
+// For error html is text of error.
+func gencfHtml(write func(w io.Writer) error) template.HTML {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		return template.HTML(template.HTMLEscapeString(err.Error()))
+	}
+	return template.HTML(buf.String())
+}
+
+// gencfSlots is functions for write html of nested fields
+type gencfSlots []gencfSlot
+
+// gencfSlot is function for write html of nested field. Argument i of
+// function is index of slice element.
+type gencfSlot struct {
+	write func(w io.Writer, i int) error
+	i     int
+}
+
+// gencfSlotMarker is prefix of html comment at place of slot. Comments of
+// widget templates are removed by html/template, so all such comments in
+// result of template execution are slots.
+const gencfSlotMarker = "<!--gencf:"
+
+// add return html comment at place of html written by function write with
+// argument i
+func (s *gencfSlots) add(write func(w io.Writer, i int) error, i int) template.HTML {
+	*s = append(*s, gencfSlot{write: write, i: i})
+	return template.HTML(gencfSlotMarker + strconv.Itoa(len(*s)-1) + "-->")
+}
+
+// slot return html comment at place of html written by function write
+func (s *gencfSlots) slot(write func(w io.Writer) error) template.HTML {
+	return s.add(func(w io.Writer, _ int) error {
+		return write(w)
+	}, 0)
+}
+
+// items return html comments at places of html of n slice elements. Html
+// of element with index i is written by function write.
+func (s *gencfSlots) items(n int, write func(w io.Writer, i int) error) []template.HTML {
+	items := make([]template.HTML, n)
+	for i := range items {
+		items[i] = s.add(write, i)
+	}
+	return items
+}
+
+// gencfSlotWriter write html of widget into w and html of slots at places
+// of slot comments. Html of template action is written by one call of
+// Write, so slot comment is not separated between calls.
+type gencfSlotWriter struct {
+	w     io.Writer
+	name  string
+	slots gencfSlots
+}
+
+func (sw *gencfSlotWriter) Write(p []byte) (int, error) {
+	html := p
+	for {
+		begin := bytes.Index(html, []byte(gencfSlotMarker))
+		if begin < 0 {
+			break
+		}
+		end := bytes.Index(html[begin:], []byte("-->"))
+		if end < 0 {
+			break
+		}
+		i, err := strconv.Atoi(string(html[begin+len(gencfSlotMarker) : begin+end]))
+		if err != nil || i < 0 || len(sw.slots) <= i {
+			return 0, fmt.Errorf("not valid slot in widget template %s", sw.name)
+		}
+		if 0 < begin {
+			if _, err := sw.w.Write(html[:begin]); err != nil {
+				return 0, err
+			}
+		}
+		if err := sw.slots[i].write(sw.w, sw.slots[i].i); err != nil {
+			return 0, err
+		}
+		html = html[begin+end+len("-->"):]
+	}
+	if 0 < len(html) {
+		if _, err := sw.w.Write(html); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// gencfRender write result of widget template execution into w. Data of
+// template is created by function data. Html of slots is written by slot
+// functions directly into w during template execution, so html of widget
+// is not buffered.
+func gencfRender(w io.Writer, name string, data func(s *gencfSlots) interface{}) error {
+	sw := gencfSlotWriter{w: w, name: name}
+	d := data(&sw.slots)
+	if len(sw.slots) == 0 {
+		return gencfWrite(w, name, d)
+	}
+	return gencfWrite(&sw, name, d)
+}
+`
+
+// runtime return Go source with types and functions for execute widget
+// templates. Parts of runtime for not used features are not generated,
+// except runtime in separate file shared by several generations.
+func (g *generator) runtime() []byte {
+	var buf bytes.Buffer
+	part := func(source string, imports ...string) {
+		for _, imp := range imports {
+			g.addImport(imp)
+		}
+		buf.WriteString(source)
+	}
+	all := !g.cfg.EmbedRuntime
+	part(widgetsRuntime, "bytes", "fmt", "html/template", "io", "strconv", "strings", "unicode")
+	part(decodeRuntime, "fmt", "net/url", "sort", "strconv", "strings")
+	part(validateRuntime, "regexp", "sync")
+	part(handlerRuntime, "bytes", "context", "fmt", "io", "io/ioutil", "mime", "mime/multipart",
+		"net/http", "net/url", "sort", "strconv", "strings")
+	part(csrfRuntime, "crypto/hmac", "crypto/rand", "crypto/sha256", "crypto/subtle",
+		"encoding/base64", "fmt", "net/http")
+	if all || g.uses(func(form *Form) bool { return g.hasFile(form, map[string]bool{}) }) {
+		part(fileRuntime, "io", "io/ioutil", "mime", "mime/multipart", "net/http", "path", "strings")
+	}
+	part(scriptRuntime, "io", "net/http")
+	part(i18nRuntime, "encoding/json", "fmt", "io", "net/http", "strings", "sync")
+	if all || g.uses(hasShowIf) {
+		part(showIfRuntime, "fmt", "html/template")
+	}
+	if all || g.uses(func(form *Form) bool { return form.Layout == LayoutWizard }) {
+		part(wizardRuntime, "crypto/subtle", "encoding/base64", "fmt", "html/template", "io",
+			"net/http", "net/url", "strconv", "strings", "time")
+	}
+
+	buf.WriteString("\n// gencfScript is source of script for add elements of slices\n")
+	buf.WriteString("const gencfScript = `")
+	buf.WriteString(strings.Replace(script, "`", "` + \"`\" + `", -1))
+	buf.WriteString("`\n")
+
+	if g.cfg.Mode == ModeTemplate {
+		g.addImport("embed")
+		var names []string
+		for _, f := range g.templates {
+			names = append(names, f.Name)
+		}
+		if all {
+			// templates of all generations
+			names = []string{templateFilename("*")}
+		}
+		buf.WriteString(fmt.Sprintf("\n//go:embed %s\n", strings.Join(names, " ")))
+		buf.WriteString("var gencfFS embed.FS\n\n")
+		buf.WriteString("var gencfTemplates = template.Must(template.ParseFS(gencfFS, ")
+		for i, name := range names {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(fmt.Sprintf("%q", name))
+		}
+		buf.WriteString("))\n")
+		return buf.Bytes()
+	}
+
+	buf.WriteString("\nvar gencfTemplates = template.Must(template.New(\"gencf\").Parse(gencfWidgets))\n")
+	buf.WriteString("\n// gencfWidgets is source of widget templates\n")
+	buf.WriteString("const gencfWidgets = `")
+	buf.WriteString(strings.Replace(widgetsSource(g.widgets), "`", "` + \"`\" + `", -1))
+	buf.WriteString("`\n")
+	return buf.Bytes()
+}
+
+// uses return true if feature is used by one of forms
+func (g *generator) uses(feature func(form *Form) bool) bool {
+	for _, form := range g.forms {
+		if feature(form) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,383 @@
+package gencf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// widgetWizard is name of widget template for step of wizard
+const widgetWizard = "wizard"
+
+// wizardStepID is name of html element with fields of step of wizard
+const wizardStepID = "_step"
+
+// wizardRuntime is Go source of types and functions of multi-step forms
+const wizardRuntime = `
+// WizardStore keep form values between steps of wizard
+type WizardStore interface {
+	// Save return token of form values. Token is value of hidden html
+	// input of next step.
+	Save(w http.ResponseWriter, r *http.Request, values url.Values) (token string, err error)
+
+	// Load return form values of token
+	Load(r *http.Request, token string) (url.Values, error)
+}
+
+// NewSignedStore return store of form values in hidden html input
+// signed by key (HMAC-SHA256). Values are not encrypted. Key is secret
+// random bytes with length at least 32 bytes, for shorter key error is
+// returned. Token is valid during ttl after save, by default 1 hour.
+// Token is not bound to user and may be used several times before
+// expiration.
+func NewSignedStore(key []byte, ttl time.Duration) (WizardStore, error) {
+	if err := gencfCheckKey("NewSignedStore", key); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	return gencfSignedStore{key: key, ttl: ttl}, nil
+}
+
+// gencfNow return current time
+var gencfNow = time.Now
+
+// gencfSignedStore is store of form values in hidden html input.
+// Token is "<expiration unix time>.<base64 of values>.<signature>".
+type gencfSignedStore struct {
+	key []byte
+	ttl time.Duration
+}
+
+func (s gencfSignedStore) Save(w http.ResponseWriter, r *http.Request, values url.Values) (token string, err error) {
+	data := strconv.FormatInt(gencfNow().Add(s.ttl).Unix(), 10) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(values.Encode()))
+	return data + "." + gencfSign(s.key, data), nil
+}
+
+func (s gencfSignedStore) Load(r *http.Request, token string) (url.Values, error) {
+	dot := strings.LastIndex(token, ".")
+	if dot < 0 || subtle.ConstantTimeCompare([]byte(token[dot+1:]), []byte(gencfSign(s.key, token[:dot]))) != 1 {
+		return nil, fmt.Errorf("signature of wizard state is not valid")
+	}
+	parts := strings.SplitN(token[:dot], ".", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("not valid wizard state")
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("not valid wizard state: %v", err)
+	}
+	if gencfNow().Unix() > expires {
+		return nil, fmt.Errorf("wizard state is expired")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("not valid wizard state: %v", err)
+	}
+	return url.ParseQuery(string(data))
+}
+
+const (
+	// gencfWizardState is name of hidden html input with token of
+	// wizard state
+	gencfWizardState = "_state"
+
+	// gencfWizardButton is name of submit buttons of previous steps
+	gencfWizardButton = "_wizard"
+
+	// gencfWizardStep is name of index of current step in wizard state
+	gencfWizardStep = "_step"
+)
+
+// gencfWizard is data of wizard widget template
+type gencfWizard struct {
+	// ID of html element with fields of step
+	ID string
+
+	// Step is index of current step
+	Step int
+
+	// Steps is titles of steps. Last step is review of all fields.
+	Steps []string
+
+	// Review is true for last step
+	Review bool
+
+	// Back is label of button of previous step
+	Back string
+
+	// HTML of fields of step
+	HTML template.HTML
+}
+
+// gencfWizardValue is pointer to value of wizard form
+type gencfWizardValue interface {
+	gencfFormValue
+	writeStep(w io.Writer, step int, opts FormOptions) error
+
+	// wizardSteps return names of html inputs of steps
+	wizardSteps() [][]string
+}
+
+// gencfWizardHandler is http handler of wizard form
+type gencfWizardHandler struct {
+	*gencfHandler
+	store WizardStore
+}
+
+// ServeHTTP show step of wizard for GET request. For POST request values
+// of step are validated and next step is shown. Values of all steps are
+// submitted from review step.
+func (h *gencfWizardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.render(w, r, url.Values{}, 0, nil, http.StatusOK)
+
+	case http.MethodPost:
+		if _, ok := h.parse(w, r); !ok {
+			return
+		}
+		if r.MultipartForm != nil {
+			defer r.MultipartForm.RemoveAll()
+		}
+		state := url.Values{}
+		if token := r.PostForm.Get(gencfWizardState); token != "" {
+			values, err := h.store.Load(r, token)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			// values of store are not changed
+			for key, v := range values {
+				state[key] = v
+			}
+		}
+		value := h.value().(gencfWizardValue)
+		steps := value.wizardSteps()
+		step, err := strconv.Atoi(state.Get(gencfWizardStep))
+		if err != nil || step < 0 || len(steps) < step {
+			step = 0
+		}
+		if step < len(steps) {
+			// values of step
+			for key := range state {
+				if gencfHasName(steps[step], key) {
+					delete(state, key)
+				}
+			}
+			for key, values := range r.PostForm {
+				if gencfHasName(steps[step], key) {
+					state[key] = values
+				}
+			}
+		}
+
+		if action := r.PostForm.Get(gencfActionName); action != "" {
+			// change of slice by submit button without script
+			if err := gencfAction(state, nil, action); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			h.render(w, r, state, step, nil, http.StatusOK)
+			return
+		}
+		if button := r.PostForm.Get(gencfWizardButton); button != "" {
+			// previous step
+			n := step - 1
+			if button != "back" {
+				n, err = strconv.Atoi(button)
+			}
+			if err != nil || n < 0 || step < n {
+				http.Error(w, "not valid step of wizard: "+button, http.StatusBadRequest)
+				return
+			}
+			h.render(w, r, state, n, nil, http.StatusOK)
+			return
+		}
+
+		err = value.fromForm(state, nil, true)
+		if step < len(steps) {
+			// only values of step are validated
+			errs := FormErrors{}
+			if all, ok := err.(FormErrors); ok {
+				for name, message := range all {
+					if gencfHasName(steps[step], name) {
+						errs[name] = message
+					}
+				}
+			}
+			if len(errs) > 0 {
+				h.render(w, r, state, step, errs, http.StatusUnprocessableEntity)
+				return
+			}
+			h.render(w, r, state, step+1, nil, http.StatusOK)
+			return
+		}
+		if err == nil {
+			err = h.submit(r.Context(), value)
+		}
+		if err != nil {
+			errs, ok := err.(FormErrors)
+			if !ok {
+				errs = FormErrors{"": err.Error()}
+			}
+			// first step with error
+		search:
+			for step = 0; step < len(steps); step++ {
+				for name := range errs {
+					if gencfHasName(steps[step], name) {
+						break search
+					}
+				}
+			}
+			h.render(w, r, state, step, errs, http.StatusUnprocessableEntity)
+			return
+		}
+		h.done(w, r)
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// render write html page with step of wizard for form values of state
+func (h *gencfWizardHandler) render(w http.ResponseWriter, r *http.Request,
+	state url.Values, step int, errs FormErrors, status int) {
+	value := h.value().(gencfWizardValue)
+	value.fromForm(state, nil, false)
+	state.Set(gencfWizardStep, strconv.Itoa(step))
+	token, err := h.store.Save(w, r, state)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.write(w, r, errs, map[string]string{gencfWizardState: token}, status,
+		func(w io.Writer, opts FormOptions) error {
+			return value.writeStep(w, step, opts)
+		})
+}
+
+// gencfHasName return true if name is one of names or name of their
+// nested html inputs
+func gencfHasName(names []string, name string) bool {
+	for _, n := range names {
+		if name == n || strings.HasPrefix(name, n+".") || strings.HasPrefix(name, n+"[") {
+			return true
+		}
+	}
+	return false
+}
+`
+
+// wizardName return name of function for create http handler of wizard
+// of struct
+func wizardName(name string) string {
+	return "New" + name + "Wizard"
+}
+
+// wizard write Go source of steps and handler of wizard form
+func (g *generator) wizard(form *Form) error {
+	if g.hasFile(form, map[string]bool{}) {
+		return fmt.Errorf("Struct %s: uploaded files are not supported by wizard", form.Name)
+	}
+	label, help := splitDoc(form.Doc)
+	for _, message := range []string{"Next", "Back", "Review"} {
+		g.message(message, message)
+	}
+
+	var titles, names, html bytes.Buffer
+	for i, b := range g.blocks(form.Fields) {
+		var paths []string
+		if b.field != nil {
+			f := b.field
+			title := f.Label
+			if title == "" {
+				title = f.Name
+			}
+			titles.WriteString(fmt.Sprintf("gencfText(opts.Locale, %q, %q),\n", f.Struct+"."+f.Path, title))
+			paths = append(paths, f.Path)
+		} else {
+			s := b.groups[0]
+			titles.WriteString(fmt.Sprintf("gencfText(opts.Locale, %q, %q),\n", s.name(), s.group))
+			for _, f := range s.fields {
+				paths = append(paths, f.Path)
+			}
+		}
+		names.WriteString("{")
+		for j, path := range paths {
+			if j > 0 {
+				names.WriteString(", ")
+			}
+			names.WriteString(fmt.Sprintf("%q", form.Name+"."+path))
+		}
+		names.WriteString("},\n")
+		html.WriteString(fmt.Sprintf("\tcase %d:", i))
+		g.blockToHtml(&html, b)
+	}
+
+	g.source.WriteString(fmt.Sprintf(`
+func (value %[1]s) wizardSteps() [][]string {
+	return [][]string{
+%[2]s}
+}
+
+func (value %[1]s) writeStep(w io.Writer, step int, opts FormOptions) error {
+	steps := []string{
+%[3]sgencfText(opts.Locale, "Review", "Review"),
+	}
+	if step < len(steps)-1 {
+		opts.Submit = gencfText(opts.Locale, "Next", "Next")
+	}
+	opts = gencfFormOptions(opts)
+	return gencfRender(w, %[4]q, func(s *gencfSlots) interface{} {
+		return gencfForm{
+			FormOptions: opts,
+			Label:       gencfText(opts.Locale, %[1]q, %[6]q),
+			Help:        gencfText(opts.Locale, %[8]q, %[7]q),
+			HTML: s.slot(func(w io.Writer) error {
+				return gencfRender(w, %[5]q, func(s *gencfSlots) interface{} {
+					return gencfWizard{
+						ID:     gencfID(opts.ID, %[12]q),
+						Step:   step,
+						Steps:  steps,
+						Review: step == len(steps)-1,
+						Back:   gencfText(opts.Locale, "Back", "Back"),
+						HTML: s.slot(func(w io.Writer) error {
+							return value.writeStepHtml(w, step, %[9]q, opts)
+						}),
+					}
+				})
+			}),
+			JS: gencfScript,
+		}
+	})
+}
+
+func (value %[1]s) writeStepHtml(w io.Writer, step int, prefix string, opts FormOptions) (err error) {
+	switch step {
+%[10]s	default:
+		return value.writeHtml(w, prefix, opts)
+	}
+	return
+}
+
+func %[11]s(store WizardStore, onSubmit func(ctx context.Context, value *%[1]s) error, opts ...HandlerOption) http.Handler {
+	return &gencfWizardHandler{
+		gencfHandler: gencfNewHandler(
+			func() gencfFormValue {
+				return new(%[1]s)
+			},
+			func(ctx context.Context, value gencfFormValue) error {
+				return onSubmit(ctx, value.(*%[1]s))
+			},
+			opts),
+		store: store,
+	}
+}
+`, form.Name, names.String(), titles.String(), widgetForm, widgetWizard,
+		label, help, helpKey(form.Name), form.Name+".", html.String(), wizardName(form.Name),
+		form.Name+"."+wizardStepID))
+	return nil
+}